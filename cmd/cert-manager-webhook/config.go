@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// secretKeySelector references a key of a Secret in the namespace of the challenge.
+type secretKeySelector struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// solverConfig is the config of the solver given in the issuer, e.g.:
+//
+//	webhook:
+//	  groupName: acme.example.com
+//	  solverName: systems90
+//	  config:
+//	    uidSecretRef:
+//	      name: systems90-credentials
+//	      key: uid
+//	    passwordSecretRef:
+//	      name: systems90-credentials
+//	      key: password
+type solverConfig struct {
+	UIDSecretRef      secretKeySelector `json:"uidSecretRef"`
+	PasswordSecretRef secretKeySelector `json:"passwordSecretRef"`
+
+	// BaseURL overrides the URL of the Systems90 API.
+	BaseURL string `json:"baseURL,omitempty"`
+	// TTL of the challenge record in seconds.
+	TTL int `json:"ttl,omitempty"`
+}
+
+func loadConfig(cfgJSON *apiextensionsv1.JSON) (solverConfig, error) {
+	cfg := solverConfig{}
+	if cfgJSON == nil {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(cfgJSON.Raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("error decoding solver config: %w", err)
+	}
+	return cfg, nil
+}
+
+func (cfg solverConfig) options() []client.Option {
+	options := []client.Option{client.WithUserAgent("cert-manager-webhook-systems90")}
+	if cfg.BaseURL != "" {
+		options = append(options, client.WithBaseURL(cfg.BaseURL))
+	}
+	return options
+}
+
+func (cfg solverConfig) recordOptions() []client.DNSRecordOption {
+	if cfg.TTL <= 0 {
+		return nil
+	}
+	return []client.DNSRecordOption{client.DNSRecordTTL(time.Duration(cfg.TTL) * time.Second)}
+}
+
+// credentials reads the credentials referenced by the config from Secrets in the namespace.
+func (cfg solverConfig) credentials(ctx context.Context, kube kubernetes.Interface, namespace string) (client.Credentials, error) {
+	uid, err := secretValue(ctx, kube, namespace, cfg.UIDSecretRef)
+	if err != nil {
+		return client.Credentials{}, err
+	}
+	password, err := secretValue(ctx, kube, namespace, cfg.PasswordSecretRef)
+	if err != nil {
+		return client.Credentials{}, err
+	}
+	return client.Credentials{UID: uid, Password: password}, nil
+}
+
+func secretValue(ctx context.Context, kube kubernetes.Interface, namespace string, ref secretKeySelector) (string, error) {
+	if ref.Name == "" || ref.Key == "" {
+		return "", fmt.Errorf("secret reference requires name and key")
+	}
+
+	secret, err := kube.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to load secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,20 @@
+// Command cert-manager-webhook is a cert-manager ACME DNS01 webhook solver
+// for zones hosted by Systems90.
+//
+// The API group of the webhook is read from the GROUP_NAME environment variable.
+package main
+
+import (
+	"os"
+
+	"github.com/cert-manager/cert-manager/pkg/acme/webhook/cmd"
+)
+
+func main() {
+	groupName := os.Getenv("GROUP_NAME")
+	if groupName == "" {
+		panic("GROUP_NAME must be specified")
+	}
+
+	cmd.RunWebhookServer(groupName, &solver{})
+}
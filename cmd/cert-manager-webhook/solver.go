@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	whapi "github.com/cert-manager/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// solver presents DNS01 challenges as TXT records in Systems90 zones.
+// Every call logs in with the credentials of the issuer and logs out afterwards.
+type solver struct {
+	kube kubernetes.Interface
+}
+
+// Name is the solver name referenced by the issuer.
+func (s *solver) Name() string {
+	return "systems90"
+}
+
+// Initialize creates the Kubernetes client used to read credential Secrets.
+func (s *solver) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	kube, err := kubernetes.NewForConfig(kubeClientConfig)
+	if err != nil {
+		return err
+	}
+
+	s.kube = kube
+	return nil
+}
+
+// Present creates the challenge TXT record. It does nothing when the record already exists.
+func (s *solver) Present(ch *whapi.ChallengeRequest) error {
+	return s.withDomain(ch, func(ctx context.Context, dc *client.DomainClient, cfg solverConfig, name string) error {
+		records, err := dc.ListDNSRecordsContext(ctx)
+		if err != nil {
+			return err
+		}
+		if len(challengeRecords(records, name, ch.Key)) > 0 {
+			return nil
+		}
+
+		_, err = dc.AddDNSRecordContext(ctx, name, ch.Key, client.DNSTypeTXT, cfg.recordOptions()...)
+		return err
+	})
+}
+
+// CleanUp deletes the challenge TXT record, leaving records of other challenges for the name intact.
+func (s *solver) CleanUp(ch *whapi.ChallengeRequest) error {
+	return s.withDomain(ch, func(ctx context.Context, dc *client.DomainClient, cfg solverConfig, name string) error {
+		records, err := dc.ListDNSRecordsContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, rec := range challengeRecords(records, name, ch.Key) {
+			if err := dc.RemoveDNSRecordByIDContext(ctx, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *solver) withDomain(ch *whapi.ChallengeRequest, fn func(context.Context, *client.DomainClient, solverConfig, string) error) error {
+	ctx := context.Background()
+
+	cfg, err := loadConfig(ch.Config)
+	if err != nil {
+		return err
+	}
+
+	cred, err := cfg.credentials(ctx, s.kube, ch.ResourceNamespace)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewClientContext(ctx, cred, cfg.options()...)
+	if err != nil {
+		return err
+	}
+	defer c.CloseContext(ctx)
+
+	zone := strings.TrimSuffix(ch.ResolvedZone, ".")
+	dc, err := c.DomainContext(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	name, err := relativeName(ch.ResolvedFQDN, zone)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, dc, cfg, name)
+}
+
+// challengeRecords returns the TXT records of the name holding exactly the key.
+func challengeRecords(records []client.DNSRecord, name, key string) []client.DNSRecord {
+	var matched []client.DNSRecord
+	for _, rec := range records {
+		if rec.Type == client.DNSTypeTXT && strings.EqualFold(rec.Name, name) && rec.IP == key {
+			matched = append(matched, rec)
+		}
+	}
+	return matched
+}
+
+func relativeName(fqdn, zone string) (string, error) {
+	fqdn = strings.TrimSuffix(fqdn, ".")
+	if !strings.HasSuffix(strings.ToLower(fqdn), "."+strings.ToLower(zone)) {
+		return "", fmt.Errorf("%s is not in zone %s", fqdn, zone)
+	}
+	return fqdn[:len(fqdn)-len(zone)-1], nil
+}
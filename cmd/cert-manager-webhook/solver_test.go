@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	whapi "github.com/cert-manager/cert-manager/pkg/acme/webhook/apis/acme/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestPresentAndCleanUp(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	otherID := srv.AddRecord("example.com", s90test.Record{Name: "_acme-challenge.www", TTL: "60", Type: "TXT", IP: "other-key"})
+
+	s := &solver{
+		kube: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "systems90", Namespace: "certs"},
+			Data: map[string][]byte{
+				"uid":      []byte("user"),
+				"password": []byte("secret"),
+			},
+		}),
+	}
+
+	ch := &whapi.ChallengeRequest{
+		ResourceNamespace: "certs",
+		ResolvedFQDN:      "_acme-challenge.www.example.com.",
+		ResolvedZone:      "example.com.",
+		Key:               "key",
+		Config: &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf(`{
+			"uidSecretRef": {"name": "systems90", "key": "uid"},
+			"passwordSecretRef": {"name": "systems90", "key": "password"},
+			"baseURL": %q,
+			"ttl": 120
+		}`, srv.URL))},
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.Present(ch); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+	}
+
+	records := srv.Records("example.com")
+	if len(records) != 2 || records[1].IP != "key" || records[1].TTL != "120" {
+		t.Fatalf("got %+v, expected a single challenge record next to the other one", records)
+	}
+
+	if err := s.CleanUp(ch); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if records := srv.Records("example.com"); len(records) != 1 || records[0].ID != otherID {
+		t.Errorf("got %+v, expected only the other record", records)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected 0", got)
+	}
+}
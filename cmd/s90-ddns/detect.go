@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// detector finds the public address of the host for one address family.
+type detector interface {
+	detect(ctx context.Context) (netip.Addr, error)
+}
+
+// interfaceDetector takes the first public address of a network interface.
+type interfaceDetector struct {
+	name string
+	ipv6 bool
+}
+
+func (d interfaceDetector) detect(ctx context.Context) (netip.Addr, error) {
+	iface, err := net.InterfaceByName(d.name)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	for _, a := range addrs {
+		prefix, err := netip.ParsePrefix(a.String())
+		if err != nil {
+			continue
+		}
+		if addr := prefix.Addr().Unmap(); isPublic(addr) && addr.Is6() == d.ipv6 {
+			return addr, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("no public %s address on interface %s", family(d.ipv6), d.name)
+}
+
+// echoDetector asks an HTTP service echoing the address of the client, e.g. https://api.ipify.org.
+type echoDetector struct {
+	url    string
+	ipv6   bool
+	client *http.Client
+}
+
+func newEchoDetector(url string, ipv6 bool) echoDetector {
+	network := "tcp4"
+	if ipv6 {
+		network = "tcp6"
+	}
+
+	// Dialing only the given family makes the service see the address of that family.
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return echoDetector{
+		url:    url,
+		ipv6:   ipv6,
+		client: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}
+}
+
+func (d echoDetector) detect(ctx context.Context) (netip.Addr, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("request failed %s with status %s", d.url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%s returned invalid address: %w", d.url, err)
+	}
+	if addr = addr.Unmap(); addr.Is6() != d.ipv6 {
+		return netip.Addr{}, errors.New(d.url + " returned address of other family " + addr.String())
+	}
+	return addr, nil
+}
+
+func isPublic(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+func family(ipv6 bool) string {
+	if ipv6 {
+		return "IPv6"
+	}
+	return "IPv4"
+}
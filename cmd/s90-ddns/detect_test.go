@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestEchoDetector(t *testing.T) {
+	params := []struct {
+		name     string
+		status   int
+		body     string
+		ipv6     bool
+		expected string
+		err      bool
+	}{
+		{name: "ipv4", status: http.StatusOK, body: "192.0.2.1\n", expected: "192.0.2.1"},
+		{name: "ipv6", status: http.StatusOK, body: "2001:db8::1", ipv6: true, expected: "2001:db8::1"},
+		{name: "mapped ipv4", status: http.StatusOK, body: "::ffff:192.0.2.1", expected: "192.0.2.1"},
+		{name: "other family", status: http.StatusOK, body: "2001:db8::1", err: true},
+		{name: "invalid", status: http.StatusOK, body: "<html>", err: true},
+		{name: "status", status: http.StatusServiceUnavailable, body: "192.0.2.1", err: true},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(p.status)
+				w.Write([]byte(p.body))
+			}))
+			defer srv.Close()
+
+			// The test server listens on IPv4 loopback, so the detector always dials IPv4
+			// and the body stands in for the address seen by the service.
+			d := newEchoDetector(srv.URL, false)
+			d.ipv6 = p.ipv6
+
+			addr, err := d.detect(context.Background())
+			if p.err {
+				if err == nil {
+					t.Errorf("got %s, expected error", addr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %s, expected nil", err)
+			}
+			if addr.String() != p.expected {
+				t.Errorf("got %s, expected %s", addr, p.expected)
+			}
+		})
+	}
+}
+
+func TestInterfaceDetector(t *testing.T) {
+	d := interfaceDetector{name: "s90-ddns-missing"}
+	if addr, err := d.detect(context.Background()); err == nil {
+		t.Errorf("got %s, expected error for missing interface", addr)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	params := []struct {
+		addr     string
+		expected bool
+	}{
+		{addr: "8.8.8.8", expected: true},
+		{addr: "2a00:1450::1", expected: true},
+		{addr: "10.0.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "127.0.0.1"},
+		{addr: "169.254.0.1"},
+		{addr: "fd00::1"},
+		{addr: "fe80::1"},
+		{addr: "::1"},
+	}
+
+	for _, p := range params {
+		if got := isPublic(netip.MustParseAddr(p.addr)); got != p.expected {
+			t.Errorf("%s: got %t, expected %t", p.addr, got, p.expected)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Command s90-ddns keeps A and AAAA records of a host up to date with its public addresses.
+//
+// The public addresses are read from a network interface or from an HTTP service
+// echoing the address of the client. Records are replaced only when the address changes.
+// Credentials are read from the S90_UID and S90_PASSWORD environment variables,
+// the URL of the API optionally from S90_BASE_URL.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+const (
+	minBackoff = 10 * time.Second
+)
+
+type updater struct {
+	cred    client.Credentials
+	options []client.Option
+
+	zone      string
+	name      string
+	ttl       time.Duration
+	detectors map[client.DNSType]detector
+
+	// The client is kept between updates, so the session is reused.
+	client *client.Client
+	dc     *client.DomainClient
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("s90-ddns", flag.ContinueOnError)
+	zone := fs.String("zone", "", "managed zone, e.g. example.com")
+	name := fs.String("name", "", "name of the records relative to the zone, empty for the apex")
+	ttl := fs.Duration("ttl", time.Minute, "TTL of the records")
+	interval := fs.Duration("interval", 5*time.Minute, "interval between checks")
+	once := fs.Bool("once", false, "check and update once, then exit")
+	ipv4 := fs.Bool("ipv4", true, "update the A record")
+	ipv6 := fs.Bool("ipv6", false, "update the AAAA record")
+	iface := fs.String("interface", "", "read addresses from the network interface instead of the echo URLs")
+	ipv4URL := fs.String("ipv4-url", "https://api.ipify.org", "HTTP service echoing the IPv4 address")
+	ipv6URL := fs.String("ipv6-url", "https://api6.ipify.org", "HTTP service echoing the IPv6 address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *zone == "" {
+		return errors.New("missing -zone")
+	}
+	if !*ipv4 && !*ipv6 {
+		return errors.New("nothing to update, both -ipv4 and -ipv6 are disabled")
+	}
+
+	u := &updater{
+		cred:      client.Credentials{UID: os.Getenv("S90_UID"), Password: os.Getenv("S90_PASSWORD")},
+		options:   []client.Option{client.WithUserAgent("s90-ddns")},
+		zone:      *zone,
+		name:      *name,
+		ttl:       *ttl,
+		detectors: make(map[client.DNSType]detector),
+	}
+	if u.cred.UID == "" || u.cred.Password == "" {
+		return errors.New("missing credentials, set S90_UID and S90_PASSWORD")
+	}
+	if baseURL := os.Getenv("S90_BASE_URL"); baseURL != "" {
+		u.options = append(u.options, client.WithBaseURL(baseURL))
+	}
+
+	for typ, enabled := range map[client.DNSType]bool{client.DNSTypeA: *ipv4, client.DNSTypeAAAA: *ipv6} {
+		if !enabled {
+			continue
+		}
+		v6 := typ == client.DNSTypeAAAA
+		if *iface != "" {
+			u.detectors[typ] = interfaceDetector{name: *iface, ipv6: v6}
+		} else if v6 {
+			u.detectors[typ] = newEchoDetector(*ipv6URL, true)
+		} else {
+			u.detectors[typ] = newEchoDetector(*ipv4URL, false)
+		}
+	}
+
+	defer u.close(context.WithoutCancel(ctx))
+
+	if *once {
+		return u.update(ctx)
+	}
+	return u.loop(ctx, *interval)
+}
+
+// loop updates the records every interval. After a failure it retries sooner,
+// doubling the delay up to the interval.
+func (u *updater) loop(ctx context.Context, interval time.Duration) error {
+	backoff := minBackoff
+	for {
+		delay := interval
+		if err := u.update(ctx); err != nil {
+			log.Printf("update failed: %s", err)
+			delay, backoff = nextBackoff(backoff, interval)
+		} else {
+			backoff = minBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// nextBackoff returns the delay after a failure and the backoff for the next failure,
+// both capped by the interval.
+func nextBackoff(backoff, interval time.Duration) (delay, next time.Duration) {
+	return min(backoff, interval), min(backoff*2, interval)
+}
+
+// update detects the addresses and replaces the records when any of them changed.
+func (u *updater) update(ctx context.Context) error {
+	var desired []client.DNSRecord
+	for typ, d := range u.detectors {
+		addr, err := d.detect(ctx)
+		if err != nil {
+			return fmt.Errorf("detecting %s address: %w", typ, err)
+		}
+		desired = append(desired, client.DNSRecord{
+			Name: u.name,
+			TTL:  u.ttl,
+			Type: typ,
+			IP:   addr.String(),
+		})
+	}
+
+	dc, err := u.domain(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := dc.ApplyContext(ctx, desired)
+	if err != nil {
+		return err
+	}
+	for _, rec := range plan.Add {
+		log.Printf("set %s %s to %s", rec.Type, fqdn(rec.Name, u.zone), rec.IP)
+	}
+	return nil
+}
+
+// domain returns the client of the zone, logging in on the first call.
+func (u *updater) domain(ctx context.Context) (*client.DomainClient, error) {
+	if u.dc != nil {
+		return u.dc, nil
+	}
+
+	if u.client == nil {
+		c, err := client.NewClientContext(ctx, u.cred, u.options...)
+		if err != nil {
+			return nil, err
+		}
+		u.client = c
+	}
+
+	dc, err := u.client.DomainContext(ctx, u.zone)
+	if err != nil {
+		return nil, err
+	}
+	u.dc = dc
+	return dc, nil
+}
+
+// close logs out the session kept between updates.
+func (u *updater) close(ctx context.Context) {
+	if u.client == nil {
+		return
+	}
+	if err := u.client.CloseContext(ctx); err != nil {
+		log.Printf("logout failed: %s", err)
+	}
+	u.client, u.dc = nil, nil
+}
+
+func fqdn(name, zone string) string {
+	if name == "" {
+		return zone
+	}
+	return name + "." + zone
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+type staticDetector struct {
+	addr netip.Addr
+}
+
+func (d *staticDetector) detect(ctx context.Context) (netip.Addr, error) {
+	return d.addr, nil
+}
+
+func TestUpdate(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddRecord("example.com", s90test.Record{Name: "home", TTL: "60", Type: "A", IP: "192.0.2.1"})
+
+	d := &staticDetector{addr: netip.MustParseAddr("192.0.2.1")}
+	u := &updater{
+		cred:      client.Credentials{UID: "user", Password: "secret"},
+		options:   []client.Option{client.WithBaseURL(srv.URL)},
+		zone:      "example.com",
+		name:      "home",
+		ttl:       time.Minute,
+		detectors: map[client.DNSType]detector{client.DNSTypeA: d},
+	}
+	ctx := context.Background()
+
+	records := srv.Records("example.com")
+	if len(records) != 1 {
+		t.Fatalf("got %+v, expected one record", records)
+	}
+	id := records[0].ID
+
+	// The address did not change, so the record is kept.
+	for i := 0; i < 2; i++ {
+		if err := u.update(ctx); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+		if got := srv.Records("example.com"); len(got) != 1 || got[0].ID != id {
+			t.Errorf("got %+v, expected record %s unchanged", got, id)
+		}
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected one kept between updates", got)
+	}
+
+	d.addr = netip.MustParseAddr("192.0.2.2")
+	if err := u.update(ctx); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Records("example.com"); len(got) != 1 || got[0].ID == id || got[0].IP != "192.0.2.2" {
+		t.Errorf("got %+v, expected the record replaced", got)
+	}
+
+	u.close(ctx)
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected none after close", got)
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	params := []struct {
+		name     string
+		interval time.Duration
+		expected []time.Duration
+	}{
+		{
+			name:     "doubling",
+			interval: 5 * time.Minute,
+			expected: []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 160 * time.Second, 5 * time.Minute, 5 * time.Minute},
+		},
+		{
+			name:     "short interval",
+			interval: 5 * time.Second,
+			expected: []time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			backoff := minBackoff
+			var delay time.Duration
+			for i, expected := range p.expected {
+				delay, backoff = nextBackoff(backoff, p.interval)
+				if delay != expected {
+					t.Errorf("failure %d: got %s, expected %s", i+1, delay, expected)
+				}
+			}
+
+			// The delay stays at the interval however long the failures last.
+			for i := 0; i < 100; i++ {
+				delay, backoff = nextBackoff(backoff, p.interval)
+			}
+			if delay != p.interval {
+				t.Errorf("got %s after many failures, expected %s", delay, p.interval)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Command s90-external-dns runs an external-dns webhook provider for zones hosted by Systems90.
+//
+// It is configured by environment variables:
+//
+//	S90_UID, S90_PASSWORD  credentials of the Systems90 API
+//	S90_BASE_URL           URL of the API, optional
+//	DOMAIN_FILTER          comma separated zones to manage, all by default
+//	EXCLUDE_DOMAINS        comma separated zones not to manage
+//	WEBHOOK_ADDR           address of the webhook, localhost:8888 by default
+//	HEALTH_ADDR            address of the /healthz endpoint, :8080 by default
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90externaldns"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context) error {
+	cred := client.Credentials{UID: os.Getenv("S90_UID"), Password: os.Getenv("S90_PASSWORD")}
+	if cred.UID == "" || cred.Password == "" {
+		return errors.New("missing credentials, set S90_UID and S90_PASSWORD")
+	}
+
+	options := []client.Option{client.WithUserAgent("s90-external-dns")}
+	if baseURL := os.Getenv("S90_BASE_URL"); baseURL != "" {
+		options = append(options, client.WithBaseURL(baseURL))
+	}
+
+	c, err := client.NewClientContext(ctx, cred, options...)
+	if err != nil {
+		return err
+	}
+	defer c.CloseContext(context.WithoutCancel(ctx))
+
+	provider := s90externaldns.NewProvider(c, s90externaldns.DomainFilter{
+		Include: splitList(os.Getenv("DOMAIN_FILTER")),
+		Exclude: splitList(os.Getenv("EXCLUDE_DOMAINS")),
+	})
+
+	health := http.NewServeMux()
+	health.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	servers := []*http.Server{
+		{Addr: envOr("WEBHOOK_ADDR", "localhost:8888"), Handler: s90externaldns.NewHandler(provider)},
+		{Addr: envOr("HEALTH_ADDR", ":8080"), Handler: health},
+	}
+
+	errc := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			log.Printf("listening on %s", srv.Addr)
+			errc <- srv.ListenAndServe()
+		}(srv)
+	}
+
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		srv.Shutdown(shutdownCtx)
+	}
+	return err
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
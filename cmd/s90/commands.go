@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	s90api "barglvojtech.net/systems90api/pkg/embi"
+)
+
+func (a *app) login(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	cred, err := a.cfg.credentials()
+	if err != nil {
+		return err
+	}
+
+	api := s90api.NewSystems90Api(a.cfg.apiOptions()...)
+	sid, err := api.LoginContext(ctx, cred)
+	if err != nil {
+		return err
+	}
+
+	if a.cfg.SessionFile != "" {
+		if err := client.NewFileSessionStore(a.cfg.SessionFile).Save(cred.UID, sid); err != nil {
+			return err
+		}
+	}
+
+	t := &table{columns: []string{"sid"}}
+	t.add(sid)
+	return t.write(a.stdout, a.output)
+}
+
+func (a *app) logout(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("logout", flag.ContinueOnError)
+	sid := fs.String("sid", os.Getenv("S90_SID"), "session ID to invalidate (default the stored session)")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *sid != "" {
+		api := s90api.NewSystems90Api(a.cfg.apiOptions()...)
+		return api.LogoutContext(ctx, *sid)
+	}
+
+	cred, err := a.cfg.credentials()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewClientContext(ctx, cred, append(a.cfg.options(), client.WithLazyLogin())...)
+	if err != nil {
+		return err
+	}
+	return c.LogoutContext(ctx)
+}
+
+func (a *app) domainsList(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	cred, err := a.cfg.credentials()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewClientContext(ctx, cred, a.cfg.options()...)
+	if err != nil {
+		return err
+	}
+	defer c.CloseContext(context.WithoutCancel(ctx))
+
+	domains, err := c.ListDomainsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"id", "zone"}}
+	for _, d := range domains {
+		t.add(d.DomainID, d.Zone)
+	}
+	return t.write(a.stdout, a.output)
+}
+
+// withDomain runs fn with a client of the given zone and closes it afterwards.
+func (a *app) withDomain(ctx context.Context, zone string, fn func(*client.DomainClient) error) error {
+	cred, err := a.cfg.credentials()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewClientContext(ctx, cred, a.cfg.options()...)
+	if err != nil {
+		return err
+	}
+	defer c.CloseContext(context.WithoutCancel(ctx))
+
+	dc, err := c.DomainContext(ctx, zone)
+	if err != nil {
+		return err
+	}
+	return fn(dc)
+}
+
+func (a *app) recordsList(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	return a.withDomain(ctx, args[0], func(dc *client.DomainClient) error {
+		records, err := dc.ListDNSRecordsContext(ctx)
+		if err != nil {
+			return err
+		}
+		return recordTable(records).write(a.stdout, a.output)
+	})
+}
+
+func (a *app) recordsAdd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("records add", flag.ContinueOnError)
+	ttl := fs.Duration("ttl", 30*time.Second, "TTL of the record")
+	priority := fs.Int("priority", client.PriorityUnset, "priority of MX and SRV records, -1 for none")
+	if err := parseFlags(fs, args, 4); err != nil {
+		return err
+	}
+	zone, name, typ, value := fs.Arg(0), fs.Arg(1), fs.Arg(2), fs.Arg(3)
+
+	return a.withDomain(ctx, zone, func(dc *client.DomainClient) error {
+		id, err := dc.AddDNSRecordContext(ctx, name, value, client.DNSTypeFromString(typ),
+			client.DNSRecordTTL(*ttl), client.DNSRecordPriority(*priority))
+		if err != nil {
+			return err
+		}
+		return idTable(id).write(a.stdout, a.output)
+	})
+}
+
+func (a *app) recordsDelete(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	zone, id := args[0], args[1]
+
+	return a.withDomain(ctx, zone, func(dc *client.DomainClient) error {
+		return dc.RemoveDNSRecordByIDContext(ctx, id)
+	})
+}
+
+func (a *app) recordsUpdate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("records update", flag.ContinueOnError)
+	ttl := fs.Duration("ttl", 30*time.Second, "TTL of the record")
+	priority := fs.Int("priority", client.PriorityUnset, "priority of MX and SRV records, -1 for none")
+	if err := parseFlags(fs, args, 5); err != nil {
+		return err
+	}
+	zone, id, name, typ, value := fs.Arg(0), fs.Arg(1), fs.Arg(2), fs.Arg(3), fs.Arg(4)
+
+	return a.withDomain(ctx, zone, func(dc *client.DomainClient) error {
+		newID, err := dc.UpdateDNSRecordContext(ctx, id, name, value, client.DNSTypeFromString(typ),
+			client.DNSRecordTTL(*ttl), client.DNSRecordPriority(*priority))
+		if err != nil {
+			return err
+		}
+		return idTable(newID).write(a.stdout, a.output)
+	})
+}
+
+func (a *app) zoneExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("zone export", flag.ContinueOnError)
+	out := fs.String("o", "", "write to the file instead of standard output")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	return a.withDomain(ctx, fs.Arg(0), func(dc *client.DomainClient) error {
+		if *out == "" {
+			return dc.ExportZoneContext(ctx, a.stdout)
+		}
+
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		if err := dc.ExportZoneContext(ctx, f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+func (a *app) zoneImport(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	zone, path := args[0], args[1]
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return a.withDomain(ctx, zone, func(dc *client.DomainClient) error {
+		result, err := dc.ImportZoneContext(ctx, f)
+		if result == nil {
+			return err
+		}
+
+		t := &table{columns: []string{"result", "id", "name", "type", "value"}}
+		for _, rec := range result.Added {
+			t.add("added", rec.ID, rec.Name, rec.Type.String(), rec.IP)
+		}
+		for _, rec := range result.Skipped {
+			t.add("skipped", rec.ID, rec.Name, rec.Type.String(), rec.IP)
+		}
+		for _, recErr := range result.Failed {
+			t.add("failed", "", recErr.Record.Name, recErr.Record.Type.String(), recErr.Record.IP)
+		}
+		if werr := t.write(a.stdout, a.output); werr != nil {
+			return werr
+		}
+		return err
+	})
+}
+
+func (a *app) zoneApply(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("zone apply", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only print the plan")
+	prune := fs.Bool("prune", false, "delete records not present in the file")
+	if err := parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+	zone, path := fs.Arg(0), fs.Arg(1)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return a.withDomain(ctx, zone, func(dc *client.DomainClient) error {
+		desired, err := client.ParseZone(f, dc.Zone())
+		if err != nil {
+			return err
+		}
+
+		var options []client.ApplyOption
+		if *dryRun {
+			options = append(options, client.ApplyDryRun())
+		}
+		if *prune {
+			options = append(options, client.ApplyPrune())
+		}
+
+		plan, err := dc.ApplyContext(ctx, desired, options...)
+		if plan == nil {
+			return err
+		}
+
+		t := &table{columns: []string{"action", "id", "name", "type", "value", "ttl"}}
+		for _, rec := range plan.Add {
+			t.add("add", rec.ID, rec.Name, rec.Type.String(), rec.IP, formatTTL(rec.TTL))
+		}
+		for _, rec := range plan.Delete {
+			t.add("delete", rec.ID, rec.Name, rec.Type.String(), rec.IP, formatTTL(rec.TTL))
+		}
+		if werr := t.write(a.stdout, a.output); werr != nil {
+			return werr
+		}
+		return err
+	})
+}
+
+// parseFlags parses flags given before or after the positional arguments
+// and checks that exactly n positional arguments were given.
+func parseFlags(fs *flag.FlagSet, args []string, n int) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return errUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != n {
+		return fmt.Errorf("%w: %s expects %d arguments", errUsage, fs.Name(), n)
+	}
+	return fs.Parse(positional)
+}
+
+func recordTable(records []client.DNSRecord) *table {
+	t := &table{columns: []string{"id", "name", "type", "value", "ttl", "priority", "locked"}}
+	for _, rec := range records {
+		t.add(rec.ID, rec.Name, rec.Type.String(), rec.IP, formatTTL(rec.TTL),
+			formatPriority(rec.Priority), strconv.FormatBool(rec.Locked))
+	}
+	return t
+}
+
+func formatPriority(priority int) string {
+	if priority == client.PriorityUnset {
+		return ""
+	}
+	return strconv.Itoa(priority)
+}
+
+func idTable(id string) *table {
+	t := &table{columns: []string{"id"}}
+	t.add(id)
+	return t
+}
+
+func formatTTL(ttl time.Duration) string {
+	return strconv.FormatInt(int64(ttl/time.Second), 10)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestParseFlags(t *testing.T) {
+	params := []struct {
+		name     string
+		args     []string
+		n        int
+		ttl      time.Duration
+		priority int
+		expected []string
+		err      bool
+	}{
+		{name: "no flags", args: []string{"a", "b"}, n: 2, ttl: time.Minute, priority: -1, expected: []string{"a", "b"}},
+		{name: "flags first", args: []string{"-ttl", "1h", "-priority", "10", "a", "b"}, n: 2, ttl: time.Hour, priority: 10, expected: []string{"a", "b"}},
+		{name: "flags last", args: []string{"a", "b", "-priority=5"}, n: 2, ttl: time.Minute, priority: 5, expected: []string{"a", "b"}},
+		{name: "flags between", args: []string{"a", "-ttl", "2m", "b", "-priority", "0", "c"}, n: 3, ttl: 2 * time.Minute, priority: 0, expected: []string{"a", "b", "c"}},
+		{name: "too few", args: []string{"a", "-ttl", "2m"}, n: 2, err: true},
+		{name: "too many", args: []string{"a", "b", "c"}, n: 2, err: true},
+		{name: "unknown flag", args: []string{"a", "-x", "b"}, n: 2, err: true},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			ttl := fs.Duration("ttl", time.Minute, "")
+			priority := fs.Int("priority", client.PriorityUnset, "")
+
+			err := parseFlags(fs, p.args, p.n)
+			if p.err {
+				if !errors.Is(err, errUsage) {
+					t.Errorf("got %v, expected %s", err, errUsage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %s, expected nil", err)
+			}
+			if *ttl != p.ttl || *priority != p.priority {
+				t.Errorf("got ttl %s priority %d, expected %s and %d", *ttl, *priority, p.ttl, p.priority)
+			}
+			if !slices.Equal(fs.Args(), p.expected) {
+				t.Errorf("got %v, expected %v", fs.Args(), p.expected)
+			}
+		})
+	}
+}
+
+func TestRecordsAdd(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	a := &app{
+		cfg:    &config{UID: "user", Password: "secret", BaseURL: srv.URL},
+		output: "json",
+		stdout: io.Discard,
+	}
+	ctx := context.Background()
+
+	// Without -priority, the MX record has no priority and is refused before any request.
+	err := a.recordsAdd(ctx, []string{"example.com", "", "mx", "mx.example.com."})
+	if !errors.Is(err, client.ErrInvalidRecord) {
+		t.Errorf("got %v, expected %s", err, client.ErrInvalidRecord)
+	}
+
+	// A TXT record must not get the priority -1.
+	if err := a.recordsAdd(ctx, []string{"example.com", "www", "txt", "hello"}); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if err := a.recordsAdd(ctx, []string{"example.com", "", "-priority", "10", "mx", "-ttl", "1h", "mx.example.com."}); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	records := srv.Records("example.com")
+	if len(records) != 2 {
+		t.Fatalf("got %+v, expected two records", records)
+	}
+	for _, rec := range records {
+		switch rec.Type {
+		case "TXT":
+			if rec.Priority != "" && rec.Priority != "0" {
+				t.Errorf("got priority %q, expected none for TXT", rec.Priority)
+			}
+		case "MX":
+			if rec.Priority != "10" || rec.TTL != "3600" {
+				t.Errorf("got %+v, expected priority 10 and TTL 3600", rec)
+			}
+		default:
+			t.Errorf("got type %s, expected upper-case TXT or MX", rec.Type)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	s90api "barglvojtech.net/systems90api/pkg/embi"
+)
+
+// config holds the settings read from the config file and environment.
+type config struct {
+	UID      string `json:"uid"`
+	Password string `json:"password"`
+	BaseURL  string `json:"base_url"`
+
+	// SessionFile is where session IDs are kept between invocations.
+	SessionFile string `json:"session_file"`
+}
+
+// defaultConfigPath returns the path of the config file used when none is given.
+func defaultConfigPath() string {
+	if path := os.Getenv("S90_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "s90", "config.json")
+}
+
+// defaultSessionPath returns the path of the session file used when none is configured.
+func defaultSessionPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "s90", "sessions.json")
+}
+
+// loadConfig reads the config file and overrides its values by environment variables.
+// A missing config file is not an error when it was not given explicitly.
+func loadConfig(path string, explicit bool) (*config, error) {
+	cfg := &config{SessionFile: defaultSessionPath()}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && !explicit:
+		case err != nil:
+			return nil, err
+		default:
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("config %s: %w", path, err)
+			}
+		}
+	}
+
+	if v := os.Getenv("S90_UID"); v != "" {
+		cfg.UID = v
+	}
+	if v := os.Getenv("S90_PASSWORD"); v != "" {
+		cfg.Password = v
+	}
+	if v := os.Getenv("S90_BASE_URL"); v != "" {
+		cfg.BaseURL = v
+	}
+	if v, ok := os.LookupEnv("S90_SESSION_FILE"); ok {
+		cfg.SessionFile = v
+	}
+
+	return cfg, nil
+}
+
+func (cfg *config) credentials() (client.Credentials, error) {
+	if cfg.UID == "" || cfg.Password == "" {
+		return client.Credentials{}, errors.New("missing credentials, set S90_UID and S90_PASSWORD or use a config file")
+	}
+	return client.Credentials{UID: cfg.UID, Password: cfg.Password}, nil
+}
+
+func (cfg *config) apiOptions() []s90api.Option {
+	var options []s90api.Option
+	if cfg.BaseURL != "" {
+		options = append(options, s90api.WithBaseURL(cfg.BaseURL))
+	}
+	return append(options, s90api.WithUserAgent("s90"))
+}
+
+// options returns the client options. Sessions are reused across invocations
+// unless the session file is set to an empty string.
+func (cfg *config) options() []client.Option {
+	options := []client.Option{client.WithAPIOptions(cfg.apiOptions()...)}
+	if cfg.SessionFile != "" {
+		options = append(options, client.WithSessionStore(client.NewFileSessionStore(cfg.SessionFile)))
+	}
+	return options
+}
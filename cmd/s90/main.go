@@ -0,0 +1,126 @@
+// Command s90 manages domains and DNS records of the Systems90 API.
+//
+// Credentials are read from the S90_UID and S90_PASSWORD environment variables
+// or from a JSON config file ($S90_CONFIG or s90/config.json in the user config directory)
+// with the keys uid, password, base_url and session_file.
+//
+// Sessions are kept in s90/sessions.json in the user cache directory ($S90_SESSION_FILE)
+// and reused by later invocations until logout. An empty session file disables it.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage: s90 [flags] <command> [arguments]
+
+Commands:
+  login                                 log in, store and print the session ID
+  logout [-sid SID]                     invalidate a session ID (default $S90_SID or the stored one)
+  domains list                          list managed domains
+  records list <zone>                   list DNS records
+  records add <zone> <name> <type> <value> [-ttl D] [-priority N]
+  records delete <zone> <id>
+  records update <zone> <id> <name> <type> <value> [-ttl D] [-priority N]
+  zone export <zone> [-o file]          write the zone as a master file
+  zone import <zone> <file>             add records of a master file
+  zone apply <zone> <file> [-dry-run] [-prune]
+                                        make the zone match a master file
+
+Flags:
+`
+
+// errUsage is returned by commands called with wrong arguments.
+var errUsage = errors.New("invalid usage")
+
+type app struct {
+	cfg    *config
+	output string
+	stdout io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "s90:", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("s90", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path of the config file")
+	output := fs.String("output", "table", "output format: table, json or yaml")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	path, explicit := *configPath, *configPath != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, explicit)
+	if err != nil {
+		return err
+	}
+
+	a := &app{
+		cfg:    cfg,
+		output: *output,
+		stdout: os.Stdout,
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "login":
+		return a.login(ctx, args)
+	case "logout":
+		return a.logout(ctx, args)
+	case "domains", "records", "zone":
+		if len(args) == 0 {
+			fs.Usage()
+			return errUsage
+		}
+		sub, args := args[0], args[1:]
+		if fn, ok := a.commands()[cmd+" "+sub]; ok {
+			return fn(ctx, args)
+		}
+	}
+
+	fs.Usage()
+	return fmt.Errorf("%w: unknown command %s", errUsage, cmd)
+}
+
+func (a *app) commands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"domains list":   a.domainsList,
+		"records list":   a.recordsList,
+		"records add":    a.recordsAdd,
+		"records delete": a.recordsDelete,
+		"records update": a.recordsUpdate,
+		"zone export":    a.zoneExport,
+		"zone import":    a.zoneImport,
+		"zone apply":     a.zoneApply,
+	}
+}
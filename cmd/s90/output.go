@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is a command result printed in the selected output format.
+type table struct {
+	columns []string
+	rows    [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+func (t *table) write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return t.writeTable(w)
+	case "json":
+		return t.writeJSON(w)
+	case "yaml":
+		return t.writeYAML(w)
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func (t *table) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.columns, "\t")))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (t *table) writeJSON(w io.Writer) error {
+	objects := make([]map[string]string, len(t.rows))
+	for i, row := range t.rows {
+		objects[i] = make(map[string]string, len(t.columns))
+		for j, col := range t.columns {
+			objects[i][col] = row[j]
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(objects)
+}
+
+// writeYAML writes the rows as a sequence of mappings.
+// Values are double-quoted, so they never need YAML escaping rules beyond Go's.
+func (t *table) writeYAML(w io.Writer) error {
+	if len(t.rows) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+
+	for _, row := range t.rows {
+		for j, col := range t.columns {
+			prefix := "  "
+			if j == 0 {
+				prefix = "- "
+			}
+			if _, err := fmt.Fprintf(w, "%s%s: %s\n", prefix, col, strconv.Quote(row[j])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableWrite(t *testing.T) {
+	tab := &table{columns: []string{"id", "name", "value"}}
+	tab.add("1", "www", "192.0.2.1")
+	tab.add("22", "", `say "hi"`)
+
+	params := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "table",
+			expected: "ID  NAME  VALUE\n" +
+				"1   www   192.0.2.1\n" +
+				"22        say \"hi\"\n",
+		},
+		{
+			format: "json",
+			expected: "[\n" +
+				"  {\n    \"id\": \"1\",\n    \"name\": \"www\",\n    \"value\": \"192.0.2.1\"\n  },\n" +
+				"  {\n    \"id\": \"22\",\n    \"name\": \"\",\n    \"value\": \"say \\\"hi\\\"\"\n  }\n" +
+				"]\n",
+		},
+		{
+			format: "yaml",
+			expected: "- id: \"1\"\n  name: \"www\"\n  value: \"192.0.2.1\"\n" +
+				"- id: \"22\"\n  name: \"\"\n  value: \"say \\\"hi\\\"\"\n",
+		},
+	}
+
+	for _, p := range params {
+		t.Run(p.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := tab.write(&b, p.format); err != nil {
+				t.Fatalf("got %s, expected nil", err)
+			}
+			if b.String() != p.expected {
+				t.Errorf("got %q, expected %q", b.String(), p.expected)
+			}
+		})
+	}
+
+	if err := tab.write(&strings.Builder{}, "xml"); err == nil {
+		t.Errorf("got nil, expected error for unknown format")
+	}
+}
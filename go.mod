@@ -1,3 +1,5 @@
 module barglvojtech.net/systems90api
 
 go 1.21.4
+
+require github.com/libdns/libdns v1.1.1
@@ -0,0 +1,58 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"barglvojtech.net/systems90api/internal/types"
+)
+
+// StatusError is returned by Fetch when the server responds with a status other than 200 OK
+// or when the response body reports a status other than OK.
+type StatusError struct {
+	URL        *url.URL // URL is the request URL without the session ID and password.
+	StatusCode int
+	Status     string
+	Code       types.StatusCode // Code is set only when the HTTP status is 200 OK.
+	RetryAfter time.Duration    // RetryAfter is the delay requested by the Retry-After header.
+}
+
+func (e *StatusError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("request failed %s with status %s", e.URL, e.Code)
+	}
+	return fmt.Sprintf("request failed %s with status %s", e.URL, e.Status)
+}
+
+// secretParams are the query parameters removed from URLs in returned errors.
+var secretParams = []string{"sid", "password"}
+
+// redactURL returns a copy of the URL without the secret query parameters.
+func redactURL(u *url.URL) *url.URL {
+	redacted := *u
+	query := redacted.Query()
+	for _, key := range secretParams {
+		query.Del(key)
+	}
+	redacted.RawQuery = query.Encode()
+	return &redacted
+}
+
+// redactError removes the secret query parameters from the URL of a *url.Error,
+// which http.Client.Do includes in its message.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+	redacted := *urlErr
+	redacted.URL = redactURL(u).String()
+	return &redacted
+}
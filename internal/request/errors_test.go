@@ -0,0 +1,48 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"barglvojtech.net/systems90api/internal/types"
+)
+
+func TestFetchRedactsSecrets(t *testing.T) {
+	srv, _ := failingServer(http.StatusInternalServerError, 1, nil)
+	closed, _ := failingServer(http.StatusOK, 0, nil)
+	closed.Close()
+	defer srv.Close()
+
+	params := []struct {
+		name string
+		url  string
+	}{
+		{name: "status", url: srv.URL},
+		{name: "network", url: closed.URL},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			_, err := Fetch[types.LogoutResponse](context.Background(), http.DefaultClient, func(b *Builder) {
+				b.Url(p.url)
+				b.Path("logout")
+				b.UrlParams("sid=secret-sid&password=secret-password&domain=example.com")
+			})
+			if err == nil {
+				t.Fatalf("got nil, expected error")
+			}
+			if msg := err.Error(); strings.Contains(msg, "secret") || !strings.Contains(msg, "domain=example.com") {
+				t.Errorf("got %q, expected the URL without secrets", msg)
+			}
+
+			var urlErr *url.Error
+			if p.name == "network" && !errors.As(err, &urlErr) {
+				t.Errorf("got %T, expected *url.Error", err)
+			}
+		})
+	}
+}
@@ -1,33 +1,88 @@
 package request
 
 import (
+	"context"
 	"encoding/xml"
-	"fmt"
+	"errors"
 	"net/http"
+	"time"
+
+	"barglvojtech.net/systems90api/internal/types"
 )
 
-func Fetch[T any](client *http.Client, fn func(*Builder)) (*T, error) {
+// Fetch sends the request configured by fn and decodes the XML response.
+// Transient failures are retried according to the retry policy of the builder.
+func Fetch[T any](ctx context.Context, client *http.Client, fn func(*Builder)) (*T, error) {
 	b := &Builder{}
 	fn(b)
 
-	req, err := b.build()
+	attempts := b.retry.attempts(b.idempotent)
+	for attempt := 1; ; attempt++ {
+		if b.limiter != nil {
+			if err := b.limiter.Wait(ctx, b.path); err != nil {
+				return nil, err
+			}
+		}
+
+		val, err := fetch[T](ctx, client, b)
+		if err == nil || attempt >= attempts || !retryable(ctx, err) {
+			return val, err
+		}
+
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+		if waitErr := wait(ctx, b.retry.delay(attempt, retryAfter)); waitErr != nil {
+			return val, err
+		}
+	}
+}
+
+func fetch[T any](ctx context.Context, client *http.Client, b *Builder) (*T, error) {
+	req, err := b.build(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, redactError(err)
 	}
 
 	var val T
 	defer resp.Body.Close()
-	if err := xml.NewDecoder(resp.Body).Decode(&val); err != nil {
-		return nil, err
-	}
+	decodeErr := xml.NewDecoder(resp.Body).Decode(&val)
 
 	if resp.StatusCode != http.StatusOK {
-		return &val, fmt.Errorf("request failed %s with status %s", req.URL, resp.Status)
+		statusErr := &StatusError{
+			URL:        redactURL(req.URL),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		if decodeErr != nil {
+			return nil, statusErr
+		}
+		return &val, statusErr
+	}
+
+	if decodeErr != nil {
+		return nil, decodeErr
 	}
+
+	// The API may refuse a request with 200 OK, reporting it only in the body.
+	if r, ok := any(val).(types.Response); ok {
+		if code := r.ResponseStatus().Code; code != "" && code != types.StatusOk {
+			return &val, &StatusError{
+				URL:        redactURL(req.URL),
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+				Code:       code,
+			}
+		}
+	}
+
 	return &val, nil
 }
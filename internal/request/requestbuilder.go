@@ -1,6 +1,7 @@
 package request
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/url"
@@ -20,6 +21,16 @@ type Builder struct {
 
 	urlParam string
 	payload  string
+
+	retry      RetryPolicy
+	idempotent bool
+	limiter    Limiter
+}
+
+// Limiter delays or refuses requests to keep them under a rate limit.
+type Limiter interface {
+	// Wait blocks until a request to the path may be sent.
+	Wait(ctx context.Context, path string) error
 }
 
 func (b *Builder) Url(rawUrl string) {
@@ -40,6 +51,21 @@ func (b *Builder) Path(path string) {
 	b.path = path
 }
 
+// Retry sets the policy used to retry the request after a transient failure.
+func (b *Builder) Retry(policy RetryPolicy) {
+	b.retry = policy
+}
+
+// Idempotent marks the request as safe to be sent more than once.
+func (b *Builder) Idempotent() {
+	b.idempotent = true
+}
+
+// Limit makes every attempt of the request wait for the limiter.
+func (b *Builder) Limit(limiter Limiter) {
+	b.limiter = limiter
+}
+
 func (b *Builder) UrlParams(params any) {
 	switch params.(type) {
 	case string:
@@ -66,7 +92,7 @@ func (b *Builder) Payload(payload any) {
 	}
 }
 
-func (b *Builder) build() (*http.Request, error) {
+func (b *Builder) build(ctx context.Context) (*http.Request, error) {
 	if err := errors.Join(b.errs...); err != nil {
 		return nil, err
 	}
@@ -75,7 +101,7 @@ func (b *Builder) build() (*http.Request, error) {
 	url.Path = path.Join(url.Path, b.path)
 	url.RawQuery = b.urlParam
 
-	req, err := http.NewRequest(b.method, url.String(), strings.NewReader(b.payload))
+	req, err := http.NewRequestWithContext(ctx, b.method, url.String(), strings.NewReader(b.payload))
 	if err != nil {
 		return nil, err
 	}
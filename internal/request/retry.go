@@ -0,0 +1,112 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Fetch retries requests that failed with a transient error,
+// i.e. a connection error, a timeout of the HTTP client, 429 Too Many Requests or a 5xx status.
+//
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles with every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including the one requested by Retry-After.
+	MaxDelay time.Duration
+	// RetryNonIdempotent enables retries of requests not marked as idempotent,
+	// which may then be performed more than once by the server.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy retries idempotent requests up to three times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// attempts returns the number of attempts allowed for the request.
+func (p RetryPolicy) attempts(idempotent bool) int {
+	if p.MaxAttempts < 1 || !idempotent && !p.RetryNonIdempotent {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the delay before the given retry (starting at 1),
+// using the Retry-After value when the server sent one.
+func (p RetryPolicy) delay(retry int, retryAfter time.Duration) time.Duration {
+	d := retryAfter
+	if d <= 0 {
+		d = p.BaseDelay
+		for i := 1; i < retry && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+			d *= 2
+		}
+		// Equal jitter keeps at least half of the delay and spreads concurrent clients.
+		if half := int64(d / 2); half > 0 {
+			d = time.Duration(half + rand.Int63n(half+1))
+		}
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// retryable reports whether the failed request may succeed when sent again.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == "" &&
+			(statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500)
+	}
+
+	// Errors of http.Client.Do cover connection resets, refused connections and timeouts.
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// wait sleeps for the delay, failing early when the context is done
+// or its deadline would pass before the delay.
+func wait(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses the Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/internal/types"
+)
+
+const okBody = `<response><status>OK</status></response>`
+
+// failingServer responds with the status to the first failures requests and with OK afterwards.
+func failingServer(status int, failures int32, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(okBody))
+	}))
+	return srv, &calls
+}
+
+func TestFetchRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	params := []struct {
+		name       string
+		status     int
+		failures   int32
+		idempotent bool
+		policy     RetryPolicy
+		calls      int32
+		fail       bool
+	}{
+		{name: "recovers", status: http.StatusServiceUnavailable, failures: 2, idempotent: true, policy: policy, calls: 3},
+		{name: "gives up", status: http.StatusBadGateway, failures: 5, idempotent: true, policy: policy, calls: 3, fail: true},
+		{name: "too many requests", status: http.StatusTooManyRequests, failures: 1, idempotent: true, policy: policy, calls: 2},
+		{name: "client error", status: http.StatusBadRequest, failures: 1, idempotent: true, policy: policy, calls: 1, fail: true},
+		{name: "non-idempotent", status: http.StatusServiceUnavailable, failures: 1, policy: policy, calls: 1, fail: true},
+		{name: "non-idempotent opted in", status: http.StatusServiceUnavailable, failures: 1,
+			policy: RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}, calls: 2},
+		{name: "disabled", status: http.StatusServiceUnavailable, failures: 1, idempotent: true, calls: 1, fail: true},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			srv, calls := failingServer(p.status, p.failures, nil)
+			defer srv.Close()
+
+			_, err := Fetch[types.LogoutResponse](context.Background(), srv.Client(), func(b *Builder) {
+				b.Url(srv.URL)
+				b.Retry(p.policy)
+				if p.idempotent {
+					b.Idempotent()
+				}
+			})
+			if (err != nil) != p.fail {
+				t.Errorf("got %v, expected failure %t", err, p.fail)
+			}
+			if got := calls.Load(); got != p.calls {
+				t.Errorf("got %d calls, expected %d", got, p.calls)
+			}
+		})
+	}
+}
+
+func TestFetchRetryAfter(t *testing.T) {
+	srv, calls := failingServer(http.StatusServiceUnavailable, 1, http.Header{"Retry-After": []string{"1"}})
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	start := time.Now()
+	_, err := Fetch[types.LogoutResponse](context.Background(), srv.Client(), func(b *Builder) {
+		b.Url(srv.URL)
+		b.Retry(policy)
+		b.Idempotent()
+	})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("got retry after %s, expected at least 1s", elapsed)
+	}
+
+	// A deadline before the requested delay ends the retries at once.
+	calls.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = Fetch[types.LogoutResponse](ctx, srv.Client(), func(b *Builder) {
+		b.Url(srv.URL)
+		b.Retry(policy)
+		b.Idempotent()
+	})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v, expected 503 status error", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d calls, expected 1", got)
+	}
+}
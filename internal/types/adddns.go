@@ -17,3 +17,7 @@ type AddDnsResponse struct {
 	Status Status `xml:"status"`
 	DNSID  string `xml:"dns_id"`
 }
+
+func (r AddDnsResponse) ResponseStatus() Status {
+	return r.Status
+}
@@ -8,3 +8,7 @@ type DeleteDnsRequest struct {
 type DeleteDnsResponse struct {
 	Status Status `xml:"status"`
 }
+
+func (r DeleteDnsResponse) ResponseStatus() Status {
+	return r.Status
+}
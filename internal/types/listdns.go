@@ -10,6 +10,10 @@ type ListDnsResponse struct {
 	Zone   ListDnsResponse_Zone `xml:"zone"`
 }
 
+func (r ListDnsResponse) ResponseStatus() Status {
+	return r.Status
+}
+
 type ListDnsResponse_Zone struct {
 	Records []ListDnsResponse_Record `xml:"record"`
 }
@@ -9,6 +9,10 @@ type ListDomainsResponse struct {
 	Domains ListDomainsResponse_Domains `xml:"domains"`
 }
 
+func (r ListDomainsResponse) ResponseStatus() Status {
+	return r.Status
+}
+
 type ListDomainsResponse_Domains struct {
 	Domains []ListDomainsResponse_Domain `xml:"domain"`
 }
@@ -10,3 +10,7 @@ type LoginResponse struct {
 	UID    string `xml:"uid"`
 	SID    string `xml:"sid"`
 }
+
+func (r LoginResponse) ResponseStatus() Status {
+	return r.Status
+}
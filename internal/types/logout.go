@@ -7,3 +7,7 @@ type LogoutRequest struct {
 type LogoutResponse struct {
 	Status Status `xml:"status"`
 }
+
+func (r LogoutResponse) ResponseStatus() Status {
+	return r.Status
+}
@@ -2,6 +2,11 @@ package types
 
 // Status is a type that represents the status of a response.
 type Status struct {
-	Code string `xml:"status"`
-	Text string `xml:"text"`
+	Code StatusCode `xml:"status"`
+	Text string     `xml:"text"`
+}
+
+// Response is implemented by all responses of the API.
+type Response interface {
+	ResponseStatus() Status
 }
@@ -0,0 +1,306 @@
+package zonefile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/embi"
+)
+
+// Parse reads records of a master file for the given zone.
+// Owner names are returned relative to the zone, the apex as an empty name,
+// and domain names in record data as absolute names.
+func Parse(r io.Reader, zone string) ([]embi.DNSRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := tokenize(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		zone:   fqdn(zone),
+		origin: fqdn(zone),
+	}
+
+	var records []embi.DNSRecord
+	for _, l := range lines {
+		rec, err := p.parseLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("zonefile: line %d: %w", l.num, err)
+		}
+		if rec != nil {
+			records = append(records, *rec)
+		}
+	}
+	return records, nil
+}
+
+type parser struct {
+	zone   string
+	origin string
+
+	dirTTL    time.Duration // dirTTL is the TTL set by $TTL
+	hasDirTTL bool
+	lastTTL   time.Duration
+	hasTTL    bool
+	lastOwner string
+}
+
+func (p *parser) parseLine(l line) (*embi.DNSRecord, error) {
+	tokens := l.tokens
+
+	if !l.indented && !tokens[0].quoted && strings.HasPrefix(tokens[0].text, "$") {
+		return nil, p.parseDirective(tokens)
+	}
+
+	owner := p.lastOwner
+	if !l.indented {
+		var err error
+		if owner, err = p.absolute(tokens[0].text); err != nil {
+			return nil, err
+		}
+		tokens = tokens[1:]
+	}
+	if owner == "" {
+		return nil, errors.New("missing owner name")
+	}
+	p.lastOwner = owner
+
+	name, err := p.relative(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	// RFC 2308: records without TTL use $TTL, or the TTL of the previous record when it is not set.
+	ttl, hasTTL := p.lastTTL, p.hasTTL
+	if p.hasDirTTL {
+		ttl, hasTTL = p.dirTTL, true
+	}
+	for len(tokens) > 0 && !tokens[0].quoted {
+		if v, err := parseTTL(tokens[0].text); err == nil {
+			ttl, hasTTL = v, true
+		} else if !isClass(tokens[0].text) {
+			break
+		}
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("missing record type")
+	}
+	if !hasTTL {
+		return nil, errors.New("missing TTL and no $TTL set")
+	}
+	p.lastTTL, p.hasTTL = ttl, true
+
+	// Types unknown to embi are kept with their data as written.
+	typ := embi.DNSTypeFromString(strings.ToUpper(tokens[0].text))
+
+	rec := &embi.DNSRecord{
+		Name: name,
+		TTL:  ttl,
+		Type: typ,
+	}
+	if err := p.parseRData(rec, tokens[1:]); err != nil {
+		return nil, fmt.Errorf("%s record: %w", typ, err)
+	}
+	return rec, nil
+}
+
+func (p *parser) parseDirective(tokens []token) error {
+	switch strings.ToUpper(tokens[0].text) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return errors.New("$ORIGIN expects a single domain name")
+		}
+		origin, err := p.absolute(tokens[1].text)
+		if err != nil {
+			return err
+		}
+		p.origin = origin
+	case "$TTL":
+		if len(tokens) != 2 {
+			return errors.New("$TTL expects a single value")
+		}
+		ttl, err := parseTTL(tokens[1].text)
+		if err != nil {
+			return err
+		}
+		p.dirTTL, p.hasDirTTL = ttl, true
+	default:
+		return fmt.Errorf("unsupported directive %s", tokens[0].text)
+	}
+	return nil
+}
+
+func (p *parser) parseRData(rec *embi.DNSRecord, tokens []token) error {
+	switch rec.Type {
+	case embi.DNSTypeTXT:
+		var b strings.Builder
+		for _, t := range tokens {
+			b.WriteString(unescape(t))
+		}
+		rec.IP = b.String()
+		return nil
+
+	case embi.DNSTypeCNAME, embi.DNSTypeDNAME, embi.DNSTypeNS, embi.DNSTypePTR:
+		if len(tokens) != 1 {
+			return errors.New("expects a single domain name")
+		}
+		target, err := p.absolute(tokens[0].text)
+		rec.IP = target
+		return err
+
+	case embi.DNSTypeMX:
+		if len(tokens) != 2 {
+			return errors.New("expects preference and exchange")
+		}
+		priority, err := strconv.ParseUint(tokens[0].text, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid preference %s", tokens[0].text)
+		}
+		target, err := p.absolute(tokens[1].text)
+		rec.Priority, rec.IP = int(priority), target
+		return err
+
+	case embi.DNSTypeSRV:
+		if len(tokens) != 4 {
+			return errors.New("expects priority, weight, port and target")
+		}
+		priority, err := strconv.ParseUint(tokens[0].text, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid priority %s", tokens[0].text)
+		}
+		target, err := p.absolute(tokens[3].text)
+		rec.Priority, rec.IP = int(priority), tokens[1].text+" "+tokens[2].text+" "+target
+		return err
+
+	default:
+		if len(tokens) == 0 {
+			return errors.New("missing data")
+		}
+		parts := make([]string, len(tokens))
+		for i, t := range tokens {
+			parts[i] = t.text
+			if t.quoted {
+				parts[i] = quote(unescape(t))
+			}
+		}
+		rec.IP = strings.Join(parts, " ")
+		return nil
+	}
+}
+
+// absolute returns the name as an absolute name, resolving relative names against the origin.
+func (p *parser) absolute(name string) (string, error) {
+	switch {
+	case name == "":
+		return "", errors.New("empty domain name")
+	case name == "@":
+		return p.origin, nil
+	case strings.HasSuffix(name, "."):
+		return name, nil
+	default:
+		return name + "." + p.origin, nil
+	}
+}
+
+// relative returns the absolute name relative to the zone.
+func (p *parser) relative(name string) (string, error) {
+	switch {
+	case strings.EqualFold(name, p.zone):
+		return "", nil
+	case len(name) > len(p.zone) && strings.EqualFold(name[len(name)-len(p.zone)-1:], "."+p.zone):
+		return name[:len(name)-len(p.zone)-1], nil
+	default:
+		return "", fmt.Errorf("name %s is outside of zone %s", name, p.zone)
+	}
+}
+
+// parseTTL parses a TTL given in seconds or with BIND units, e.g. 1h30m.
+func parseTTL(s string) (time.Duration, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("invalid TTL %s", s)
+	}
+	if secs, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+
+	var total, num time.Duration
+	digits := false
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			num = num*10 + time.Duration(c-'0')
+			digits = true
+			continue
+		}
+
+		unit, ok := ttlUnits[c]
+		if !ok || !digits {
+			return 0, fmt.Errorf("invalid TTL %s", s)
+		}
+		total += num * unit
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid TTL %s", s)
+	}
+	return total, nil
+}
+
+var ttlUnits = map[rune]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+func isClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	}
+	return false
+}
+
+// unescape resolves \X and \DDD escapes of a token.
+func unescape(t token) string {
+	if !strings.Contains(t.text, `\`) {
+		return t.text
+	}
+
+	var b strings.Builder
+	s := t.text
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 < len(s) && isDigits(s[i+1:i+4]) {
+			n, _ := strconv.Atoi(s[i+1 : i+4])
+			b.WriteByte(byte(n))
+			i += 3
+			continue
+		}
+		b.WriteByte(s[i+1])
+		i++
+	}
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package zonefile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type token struct {
+	text   string // text is the raw text, quotes removed but escapes kept
+	quoted bool
+}
+
+// line is a logical line of a master file, possibly spanning
+// several physical lines with parentheses.
+type line struct {
+	num      int
+	indented bool
+	tokens   []token
+}
+
+func tokenize(data string) ([]line, error) {
+	var (
+		lines []line
+		cur   = line{num: 1}
+		num   = 1
+		depth = 0
+		start = true
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if start {
+			cur.indented = c == ' ' || c == '\t'
+			start = false
+		}
+
+		switch {
+		case c == '\n':
+			num++
+			if depth == 0 {
+				if len(cur.tokens) > 0 {
+					lines = append(lines, cur)
+				}
+				cur = line{num: num}
+				start = true
+			}
+
+		case c == ' ' || c == '\t' || c == '\r':
+
+		case c == ';':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+
+		case c == '(':
+			depth++
+
+		case c == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("zonefile: line %d: unbalanced parenthesis", num)
+			}
+			depth--
+
+		case c == '"':
+			j := i + 1
+			for j < len(data) && data[j] != '"' {
+				if data[j] == '\\' {
+					j++
+				}
+				if j < len(data) && data[j] == '\n' {
+					num++
+				}
+				j++
+			}
+			if j >= len(data) {
+				return nil, fmt.Errorf("zonefile: line %d: unterminated string", num)
+			}
+			cur.tokens = append(cur.tokens, token{text: data[i+1 : j], quoted: true})
+			i = j
+
+		default:
+			j := i
+			for j < len(data) && !strings.ContainsRune(" \t\r\n;()\"", rune(data[j])) {
+				if data[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(data))
+			cur.tokens = append(cur.tokens, token{text: data[i:j]})
+			i = j - 1
+		}
+	}
+
+	if depth != 0 {
+		return nil, errors.New("zonefile: unbalanced parenthesis at end of file")
+	}
+	if len(cur.tokens) > 0 {
+		lines = append(lines, cur)
+	}
+	return lines, nil
+}
@@ -0,0 +1,116 @@
+// Package zonefile reads and writes DNS records in the RFC 1035 master file format.
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/embi"
+)
+
+// maxStringLen is the maximum length of a single character-string in TXT data.
+const maxStringLen = 255
+
+// Write renders the records as a master file with $ORIGIN set to the zone.
+func Write(w io.Writer, zone string, records []embi.DNSRecord) error {
+	bw := bufio.NewWriter(w)
+	origin := fqdn(zone)
+
+	fmt.Fprintf(bw, "$ORIGIN %s\n", origin)
+	for _, rec := range records {
+		fmt.Fprintf(bw, "%s\t%d\tIN\t%s\t%s\n",
+			ownerName(rec.Name, origin),
+			int64(rec.TTL/time.Second),
+			rec.Type,
+			rdata(rec),
+		)
+	}
+
+	return bw.Flush()
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ownerName returns the name relative to the origin, or absolute when outside of it.
+func ownerName(name, origin string) string {
+	switch {
+	case name == "" || name == "@" || fqdn(name) == origin:
+		return "@"
+	case strings.HasSuffix(fqdn(name), "."+origin):
+		return strings.TrimSuffix(fqdn(name), "."+origin)
+	default:
+		return name
+	}
+}
+
+// priority formats the priority of MX and SRV records. Records listed without
+// a priority are written with 0, as a master file cannot leave it out.
+func priority(rec embi.DNSRecord) string {
+	if rec.Priority == embi.PriorityUnset {
+		return "0"
+	}
+	return strconv.Itoa(rec.Priority)
+}
+
+// rdata formats the record data. Targets are written absolute, as the API may store them
+// without the trailing dot, which would make them relative to $ORIGIN.
+func rdata(rec embi.DNSRecord) string {
+	switch rec.Type {
+	case embi.DNSTypeCNAME, embi.DNSTypeDNAME, embi.DNSTypeNS, embi.DNSTypePTR:
+		return fqdn(rec.IP)
+	case embi.DNSTypeMX:
+		return priority(rec) + " " + fqdn(rec.IP)
+	case embi.DNSTypeSRV:
+		fields := strings.Fields(rec.IP)
+		if len(fields) == 3 {
+			fields[2] = fqdn(fields[2])
+		}
+		return priority(rec) + " " + strings.Join(fields, " ")
+	case embi.DNSTypeTXT:
+		return quoteTXT(rec.IP)
+	default:
+		return rec.IP
+	}
+}
+
+// quoteTXT quotes the value, splitting it into character-strings of allowed length.
+func quoteTXT(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	var parts []string
+	for len(value) > 0 {
+		n := min(len(value), maxStringLen)
+		parts = append(parts, quote(value[:n]))
+		value = value[n:]
+	}
+	return strings.Join(parts, " ")
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range []byte(s) {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
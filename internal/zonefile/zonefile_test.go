@@ -0,0 +1,132 @@
+package zonefile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/embi"
+)
+
+func TestWrite(t *testing.T) {
+	records := []embi.DNSRecord{
+		{Name: "", TTL: time.Hour, Type: embi.DNSTypeNS, IP: "ns.example.com."},
+		{Name: "www", TTL: time.Minute, Type: embi.DNSTypeA, IP: "192.0.2.1"},
+		{Name: "mail.example.com", TTL: time.Minute, Type: embi.DNSTypeMX, IP: "mx.example.net.", Priority: 10},
+		{Name: "backup", TTL: time.Minute, Type: embi.DNSTypeMX, IP: "mx2.example.net.", Priority: embi.PriorityUnset},
+		{Name: "_sip._tcp", TTL: time.Minute, Type: embi.DNSTypeSRV, IP: "5 5060 sip.example.com.", Priority: 20},
+		{Name: "other.example.net.", TTL: time.Minute, Type: embi.DNSTypeTXT, IP: `say "hi"`},
+	}
+
+	expected := strings.Join([]string{
+		"$ORIGIN example.com.",
+		"@\t3600\tIN\tNS\tns.example.com.",
+		"www\t60\tIN\tA\t192.0.2.1",
+		"mail\t60\tIN\tMX\t10 mx.example.net.",
+		"backup\t60\tIN\tMX\t0 mx2.example.net.",
+		"_sip._tcp\t60\tIN\tSRV\t20 5 5060 sip.example.com.",
+		"other.example.net.\t60\tIN\tTXT\t\"say \\\"hi\\\"\"",
+		"",
+	}, "\n")
+
+	var b strings.Builder
+	if err := Write(&b, "example.com", records); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := b.String(); got != expected {
+		t.Errorf("got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestWriteParse(t *testing.T) {
+	records := []embi.DNSRecord{
+		{Name: "", TTL: time.Hour, Type: embi.DNSTypeNS, IP: "ns.example.com"},
+		{Name: "", TTL: time.Hour, Type: embi.DNSTypeMX, IP: "mx.example.com", Priority: 10},
+		{Name: "www", TTL: time.Hour, Type: embi.DNSTypeCNAME, IP: "example.net"},
+		{Name: "_sip._tcp", TTL: time.Hour, Type: embi.DNSTypeSRV, IP: "5 5060 sip.example.com", Priority: 20},
+	}
+
+	var b strings.Builder
+	if err := Write(&b, "example.com", records); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	got, err := Parse(strings.NewReader(b.String()), "example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("got %d records, expected %d", len(got), len(records))
+	}
+
+	for i, rec := range records {
+		rec.IP += "."
+		if got[i] != rec {
+			t.Errorf("record %d: got %+v, expected %+v", i, got[i], rec)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	given := `$ORIGIN example.com.
+$TTL 1h
+@		IN	NS	ns.example.net.
+		IN	MX	10 mail ; relative exchange
+www	60	IN	A	192.0.2.1
+	IN	60	AAAA	2001:db8::1
+$ORIGIN sub.example.com.
+api	1m	CNAME	www.example.com.
+_sip._tcp	SRV	( 20 5
+		5060 sip )
+txt	TXT	"v=DKIM1; " "p=abc\"def" ; multi-string
+caa	CAA	0 issue "letsencrypt.org"
+1	PTR	host
+key	TYPE65280	\# 2 abcd ; unknown type
+`
+
+	expected := []embi.DNSRecord{
+		{Name: "", TTL: time.Hour, Type: embi.DNSTypeNS, IP: "ns.example.net."},
+		{Name: "", TTL: time.Hour, Type: embi.DNSTypeMX, IP: "mail.example.com.", Priority: 10},
+		{Name: "www", TTL: time.Minute, Type: embi.DNSTypeA, IP: "192.0.2.1"},
+		{Name: "www", TTL: time.Minute, Type: embi.DNSTypeAAAA, IP: "2001:db8::1"},
+		{Name: "api.sub", TTL: time.Minute, Type: embi.DNSTypeCNAME, IP: "www.example.com."},
+		{Name: "_sip._tcp.sub", TTL: time.Hour, Type: embi.DNSTypeSRV, IP: "5 5060 sip.sub.example.com.", Priority: 20},
+		{Name: "txt.sub", TTL: time.Hour, Type: embi.DNSTypeTXT, IP: `v=DKIM1; p=abc"def`},
+		{Name: "caa.sub", TTL: time.Hour, Type: embi.DNSTypeCAA, IP: `0 issue "letsencrypt.org"`},
+		{Name: "1.sub", TTL: time.Hour, Type: embi.DNSTypePTR, IP: "host.sub.example.com."},
+		{Name: "key.sub", TTL: time.Hour, Type: "TYPE65280", IP: `\# 2 abcd`},
+	}
+
+	got, err := Parse(strings.NewReader(given), "example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("got %d records, expected %d", len(got), len(expected))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("record %d: got %+v, expected %+v", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	params := []struct {
+		name  string
+		given string
+	}{
+		{name: "missing TTL", given: "www IN A 192.0.2.1\n"},
+		{name: "name outside of zone", given: "$TTL 60\nwww.example.net. A 192.0.2.1\n"},
+		{name: "unbalanced parenthesis", given: "$TTL 60\nwww A ( 192.0.2.1\n"},
+		{name: "unsupported directive", given: "$INCLUDE other.zone\n"},
+		{name: "invalid MX", given: "$TTL 60\n@ MX mail\n"},
+	}
+
+	for _, param := range params {
+		t.Run(param.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(param.given), "example.com"); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	s90api "barglvojtech.net/systems90api/pkg/embi"
+)
+
+// Plan is a set of changes computed by Apply.
+type Plan struct {
+	Add    []DNSRecord
+	Delete []DNSRecord
+}
+
+// Empty reports whether the plan has no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Delete) == 0
+}
+
+type applyConfig struct {
+	dryRun bool
+	prune  bool
+}
+
+// ApplyOption configures Apply.
+type ApplyOption func(*applyConfig)
+
+// ApplyDryRun makes Apply only compute the plan without changing the zone.
+func ApplyDryRun() ApplyOption {
+	return func(cfg *applyConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// ApplyPrune makes Apply delete all records not present in the desired records,
+// not only those sharing name and type with a desired record.
+func ApplyPrune() ApplyOption {
+	return func(cfg *applyConfig) {
+		cfg.prune = true
+	}
+}
+
+// Apply makes the zone contain the desired records and returns the executed plan.
+//
+// Records are compared by name, type, value, TTL and priority, names case-insensitively
+// and targets of types such as CNAME and MX as absolute names. Existing records
+// sharing name and type with a desired record but differing otherwise are replaced.
+// Locked records are never deleted. When adding a record fails, the records of its
+// name and type are not deleted. The returned plan lists only the deleted records.
+func (dc *DomainClient) Apply(desired []DNSRecord, options ...ApplyOption) (*Plan, error) {
+	return dc.ApplyContext(context.Background(), desired, options...)
+}
+
+// ApplyContext is like Apply but uses the given context for the requests.
+func (dc *DomainClient) ApplyContext(ctx context.Context, desired []DNSRecord, options ...ApplyOption) (*Plan, error) {
+	cfg := &applyConfig{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	existing, err := dc.listDNS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := computePlan(existing, desired, cfg.prune)
+	if cfg.dryRun {
+		return plan, nil
+	}
+
+	// Records are added before deleting and the record sets with a failed add keep
+	// their old records, so a failure never leaves a name without records.
+	var errs []error
+	failed := make(map[rrsetKey]bool)
+	for i := range plan.Add {
+		id, err := dc.addDNS(ctx, &plan.Add[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("add %s %s: %w", plan.Add[i].Type, plan.Add[i].Name, err))
+			failed[newRRSetKey(plan.Add[i])] = true
+			continue
+		}
+		plan.Add[i].ID = id
+	}
+
+	deleted := plan.Delete[:0]
+	for _, rec := range plan.Delete {
+		if failed[newRRSetKey(rec)] {
+			continue
+		}
+		if err := dc.deleteDNS(ctx, rec.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s %s (%s): %w", rec.Type, rec.Name, rec.ID, err))
+			continue
+		}
+		deleted = append(deleted, rec)
+	}
+	plan.Delete = deleted
+
+	return plan, errors.Join(errs...)
+}
+
+type rrsetKey struct {
+	name string
+	typ  s90api.DNSType
+}
+
+// newRRSetKey returns the key of the record set of the record. Names are case-insensitive.
+func newRRSetKey(rec DNSRecord) rrsetKey {
+	return rrsetKey{strings.ToLower(rec.Name), rec.Type}
+}
+
+func computePlan(existing, desired []DNSRecord, prune bool) *Plan {
+	plan := &Plan{}
+	kept := make([]bool, len(existing))
+	managed := make(map[rrsetKey]bool)
+
+	for _, want := range desired {
+		// Records given only by typed data are compared by their wire values.
+		if want.IP == "" && want.Data != nil {
+			want.SetData(want.Data)
+		}
+		managed[newRRSetKey(want)] = true
+
+		found := false
+		for i, have := range existing {
+			if !kept[i] && sameRecord(have, want) {
+				kept[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			plan.Add = append(plan.Add, want)
+		}
+	}
+
+	for i, have := range existing {
+		if kept[i] || have.Locked {
+			continue
+		}
+		if prune || managed[newRRSetKey(have)] {
+			plan.Delete = append(plan.Delete, have)
+		}
+	}
+
+	return plan
+}
+
+func sameRecord(a, b DNSRecord) bool {
+	return sameRecordData(a, b) && a.TTL == b.TTL
+}
+
+// sameRecordData reports whether the records are equal regardless of TTL.
+// Names are compared case-insensitively and targets as absolute names,
+// as the API stores them as entered while ParseZone returns them absolute.
+func sameRecordData(a, b DNSRecord) bool {
+	return strings.EqualFold(a.Name, b.Name) &&
+		a.Type == b.Type &&
+		normalizeTarget(a).IP == normalizeTarget(b).IP &&
+		samePriority(a, b)
+}
+
+// samePriority reports whether the records have the same priority,
+// treating zero and s90api.PriorityUnset as equal for types without a priority.
+func samePriority(a, b DNSRecord) bool {
+	if a.Priority == b.Priority {
+		return true
+	}
+	unset := func(p int) bool { return p == 0 || p == s90api.PriorityUnset }
+	return !a.Type.HasPriority() && unset(a.Priority) && unset(b.Priority)
+}
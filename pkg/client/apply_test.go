@@ -0,0 +1,220 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestComputePlan(t *testing.T) {
+	existing := []DNSRecord{
+		{ID: "1", Name: "www", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.1"},
+		{ID: "2", Name: "www", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.2"},
+		{ID: "3", Name: "", TTL: time.Hour, Type: DNSTypeNS, IP: "ns.example.com", Locked: true},
+		{ID: "4", Name: "old", TTL: time.Minute, Type: DNSTypeCNAME, IP: "www"},
+		{ID: "5", Name: "", TTL: time.Minute, Type: DNSTypeMX, IP: "mx.example.com", Priority: 10},
+	}
+	desired := []DNSRecord{
+		{Name: "www", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.1"},
+		{Name: "www", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.3"},
+		{Name: "", TTL: time.Minute, Type: DNSTypeMX, IP: "mx.example.com", Priority: 20},
+		{Name: "", TTL: time.Minute, Type: DNSTypeNS, IP: "ns2.example.com"},
+	}
+
+	type param struct {
+		name    string
+		prune   bool
+		added   []string
+		deleted []string
+	}
+
+	params := []param{
+		{
+			name:    "managed record sets only",
+			added:   []string{"192.0.2.3", "mx.example.com", "ns2.example.com"},
+			deleted: []string{"2", "5"},
+		},
+		{
+			name:    "prune unmanaged records",
+			prune:   true,
+			added:   []string{"192.0.2.3", "mx.example.com", "ns2.example.com"},
+			deleted: []string{"2", "4", "5"},
+		},
+	}
+
+	for _, param := range params {
+		t.Run(param.name, func(t *testing.T) {
+			plan := computePlan(existing, desired, param.prune)
+
+			var added, deleted []string
+			for _, rec := range plan.Add {
+				added = append(added, rec.IP)
+			}
+			for _, rec := range plan.Delete {
+				deleted = append(deleted, rec.ID)
+			}
+
+			if !slices.Equal(added, param.added) {
+				t.Errorf("got added %v, expected %v", added, param.added)
+			}
+			if !slices.Equal(deleted, param.deleted) {
+				t.Errorf("got deleted %v, expected %v", deleted, param.deleted)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	wwwID := srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	ftpID := srv.AddRecord("example.com", s90test.Record{Name: "ftp", TTL: "60", Type: "A", IP: "192.0.2.10"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	desired := []DNSRecord{
+		// The fake server refuses records without TTL.
+		{Name: "www", Type: DNSTypeA, IP: "192.0.2.2"},
+		{Name: "ftp", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.11"},
+	}
+
+	plan, err := dc.Apply(desired, ApplyDryRun())
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(plan.Add) != 2 || len(plan.Delete) != 2 {
+		t.Errorf("got %+v, expected two adds and two deletes", plan)
+	}
+	if got := srv.Records("example.com"); len(got) != 2 {
+		t.Errorf("got %+v, expected dry run to keep the zone", got)
+	}
+
+	plan, err = dc.Apply(desired)
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("got %v, expected %s for the www record", err, ErrBadRequest)
+	}
+	if len(plan.Delete) != 1 || plan.Delete[0].ID != ftpID {
+		t.Errorf("got %+v, expected only ftp record deleted", plan.Delete)
+	}
+
+	var ips []string
+	for _, rec := range srv.Records("example.com") {
+		ips = append(ips, rec.IP)
+		if rec.IP == "192.0.2.1" && rec.ID != wwwID {
+			t.Errorf("got %+v, expected the old www record", rec)
+		}
+	}
+	slices.Sort(ips)
+	if expected := []string{"192.0.2.1", "192.0.2.11"}; !slices.Equal(ips, expected) {
+		t.Errorf("got %v, expected %v", ips, expected)
+	}
+}
+
+func TestApplyExportedZone(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	// The API stores targets as entered, here without the trailing dot.
+	srv.AddRecord("example.com", s90test.Record{Name: "", TTL: "3600", Type: "NS", IP: "ns.example.com"})
+	srv.AddRecord("example.com", s90test.Record{Name: "", TTL: "3600", Type: "MX", IP: "MX.example.com", Priority: "10"})
+	srv.AddRecord("example.com", s90test.Record{Name: "WWW", TTL: "3600", Type: "CNAME", IP: "example.net"})
+	srv.AddRecord("example.com", s90test.Record{Name: "_sip._tcp", TTL: "3600", Type: "SRV", IP: "5 5060 sip.example.com", Priority: "20"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	var zone strings.Builder
+	if err := dc.ExportZone(&zone); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	desired, err := ParseZone(strings.NewReader(strings.ToLower(zone.String())), "example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	plan, err := dc.Apply(desired)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("got %+v, expected no changes", plan)
+	}
+}
+
+// failDeleteTransport fails deleting the record with the ID.
+type failDeleteTransport struct {
+	id string
+}
+
+func (t failDeleteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/domain_delete_dns" && strings.Contains(req.URL.RawQuery, "="+t.id) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Status:     "400 Bad Request",
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestApplyFailedDelete(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	wwwID := srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	ftpID := srv.AddRecord("example.com", s90test.Record{Name: "ftp", TTL: "60", Type: "A", IP: "192.0.2.10"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"},
+		WithBaseURL(srv.URL), WithTransport(failDeleteTransport{id: wwwID}))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	plan, err := dc.Apply([]DNSRecord{
+		{Name: "www", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.2"},
+		{Name: "ftp", TTL: time.Minute, Type: DNSTypeA, IP: "192.0.2.11"},
+	})
+	if err == nil {
+		t.Errorf("got nil, expected error for the www record")
+	}
+	if len(plan.Add) != 2 || len(plan.Delete) != 1 || plan.Delete[0].ID != ftpID {
+		t.Errorf("got %+v, expected both added and only ftp record deleted", plan)
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -12,34 +13,90 @@ var (
 	ErrDomainNotManaged = errors.New("domain not managed")
 )
 
+// Client is a client of the Systems90 API.
+// It logs in again when the session expires.
 type Client struct {
-	api *s90api.Systems90Api
-	sid string
+	session       *session
+	conflictCheck bool
 }
 
-// NewClient creates a new client for the Systems90 API.
-func NewClient(cred s90api.Credentials) (*Client, error) {
-	api := s90api.NewSystems90Api()
-	sid, err := api.Login(cred)
+// NewClient creates a new client for the Systems90 API and logs in,
+// unless the WithLazyLogin option is given.
+func NewClient(cred s90api.Credentials, options ...Option) (*Client, error) {
+	return NewClientContext(context.Background(), cred, options...)
+}
 
-	if err != nil {
-		return nil, err
+// NewClientContext is like NewClient but uses the given context for the login request.
+func NewClientContext(ctx context.Context, cred s90api.Credentials, options ...Option) (*Client, error) {
+	cfg := &clientConfig{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	c := &Client{
+		session: &session{
+			api:         s90api.NewSystems90Api(cfg.apiOptions...),
+			cred:        cred,
+			store:       cfg.store,
+			idleTimeout: cfg.idleTimeout,
+		},
+		conflictCheck: !cfg.noConflictCheck,
+	}
+
+	if !cfg.lazyLogin {
+		if _, err := c.session.acquire(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Client{
-		api: api,
-		sid: sid,
-	}, nil
+	return c, nil
 }
 
 // Close closes the client and logs out from the API.
+// With a session store, the session is kept for other clients instead.
 func (c *Client) Close() error {
-	return c.api.Logout(c.sid)
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but uses the given context for the logout request.
+func (c *Client) CloseContext(ctx context.Context) error {
+	return c.session.close(ctx)
+}
+
+// Logout logs out from the API and removes the session from the session store.
+// The next request logs in again.
+func (c *Client) Logout() error {
+	return c.LogoutContext(context.Background())
+}
+
+// LogoutContext is like Logout but uses the given context for the logout request.
+func (c *Client) LogoutContext(ctx context.Context) error {
+	return c.session.logout(ctx)
+}
+
+// ListDomains returns all domains managed by the logged in UID.
+func (c *Client) ListDomains() ([]Domain, error) {
+	return c.ListDomainsContext(context.Background())
+}
+
+// ListDomainsContext is like ListDomains but uses the given context for the request.
+func (c *Client) ListDomainsContext(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	err := c.session.do(ctx, func(sid string) (err error) {
+		domains, err = c.session.api.ListDomainsContext(ctx, sid)
+		return err
+	})
+	return domains, err
 }
 
 // Domain returns a DomainClient for the given domain.
 func (c *Client) Domain(zone string) (*DomainClient, error) {
-	domains, err := c.api.ListDomains(c.sid)
+	return c.DomainContext(context.Background(), zone)
+}
+
+// DomainContext is like Domain but uses the given context for the request.
+func (c *Client) DomainContext(ctx context.Context, zone string) (*DomainClient, error) {
+	domains, err := c.ListDomainsContext(ctx)
 
 	if err != nil {
 		return nil, err
@@ -57,9 +114,16 @@ func (c *Client) Domain(zone string) (*DomainClient, error) {
 		return nil, fmt.Errorf("systems90: %w (%s)", ErrDomainNotManaged, zone)
 	}
 
+	return c.DomainFor(Domain{DomainID: domainID, Zone: zone}), nil
+}
+
+// DomainFor returns a DomainClient for a domain returned by ListDomains.
+// Unlike Domain, it does not make any request.
+func (c *Client) DomainFor(d Domain) *DomainClient {
 	return &DomainClient{
-		api:      c.api,
-		sid:      c.sid,
-		domainID: domainID,
-	}, nil
+		session:       c.session,
+		domainID:      d.DomainID,
+		zone:          d.Zone,
+		conflictCheck: c.conflictCheck,
+	}
 }
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestRelogin(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	other, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	srv.ExpireSessions()
+
+	if _, err := dc.AddDNSRecord("www", "192.0.2.1", DNSTypeA); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if records, err := other.ListDNSRecords(); err != nil || len(records) != 1 {
+		t.Fatalf("got %v and %v, expected single record", records, err)
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1 shared session", got)
+	}
+}
+
+func TestLazyLogin(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL), WithLazyLogin())
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected 0 before first use", got)
+	}
+	if _, err := c.ListDomains(); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1 after first use", got)
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	cred := Credentials{UID: "user", Password: "secret"}
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "sessions.json"))
+
+	for i := 0; i < 3; i++ {
+		c, err := NewClient(cred, WithBaseURL(srv.URL), WithSessionStore(store))
+		if err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+		if _, err := c.ListDomains(); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+		if err := c.Close(); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1 reused session", got)
+	}
+
+	c, err := NewClient(cred, WithBaseURL(srv.URL), WithSessionStore(store), WithLazyLogin())
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if err := c.Logout(); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected 0 after logout", got)
+	}
+	if sid, err := store.Load(cred.UID); err != nil || sid != "" {
+		t.Errorf("got %q and %v, expected empty stored session", sid, err)
+	}
+}
+
+func TestIdleLogout(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL), WithIdleLogout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.Sessions() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d sessions, expected 0 after idle timeout", srv.Sessions())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := c.ListDomains(); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1 after new login", got)
+	}
+}
+
+func TestAddDNSRecordValidation(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "3600", Type: "A", IP: "192.0.2.1"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	params := []struct {
+		name  string
+		value string
+		typ   DNSType
+	}{
+		{name: "www", value: "example.net.", typ: DNSTypeCNAME},
+		{name: "WWW", value: "example.net.", typ: DNSTypeCNAME},
+		{name: "v6", value: "192.0.2.2", typ: DNSTypeAAAA},
+		{name: "", value: "example.net.", typ: DNSTypeCNAME},
+	}
+
+	for _, p := range params {
+		if _, err := dc.AddDNSRecord(p.name, p.value, p.typ); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("%s %s: got %v, expected %s", p.typ, p.name, err, ErrInvalidRecord)
+		}
+	}
+	if got := srv.Records("example.com"); len(got) != 1 {
+		t.Errorf("got %+v, expected only the seeded record", got)
+	}
+}
+
+func TestForbiddenKeepsSession(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	lockedID := srv.AddRecord("example.com", s90test.Record{Name: "ns", TTL: "3600", Type: "A", IP: "192.0.2.1", Locked: true})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	if err := dc.RemoveDNSRecordByID(lockedID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("got %v, expected %s", err, ErrForbidden)
+	}
+	if _, err := dc.UpdateDNSRecord(lockedID, "ns", "192.0.2.2", DNSTypeA); !errors.Is(err, ErrForbidden) {
+		t.Errorf("got %v, expected %s", err, ErrForbidden)
+	}
+
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1 without re-login", got)
+	}
+	if got := srv.Records("example.com"); len(got) != 1 || got[0].ID != lockedID {
+		t.Errorf("got %+v, expected only the locked record", got)
+	}
+}
+
+func TestAddDNSRecordDefaultPriority(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	if _, err := dc.AddDNSRecord("", "mx.example.com.", DNSTypeMX); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Records("example.com"); len(got) != 1 || got[0].Priority != "0" {
+		t.Errorf("got %+v, expected MX record with priority 0", got)
+	}
+}
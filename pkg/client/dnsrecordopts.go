@@ -6,7 +6,10 @@ import (
 	s90api "barglvojtech.net/systems90api/pkg/embi"
 )
 
-func applyDNSRecordOptions(rec *s90api.DNSRecord, options []dnsRecordOption) {
+// DefaultTTL is the TTL of DNS records added without the DNSRecordTTL option.
+const DefaultTTL = 30 * time.Second
+
+func applyDNSRecordOptions(rec *s90api.DNSRecord, options []DNSRecordOption) {
 	for _, opt := range dnsRecordDefaults {
 		opt(rec)
 	}
@@ -15,21 +18,23 @@ func applyDNSRecordOptions(rec *s90api.DNSRecord, options []dnsRecordOption) {
 	}
 }
 
-type dnsRecordOption func(*s90api.DNSRecord)
+// DNSRecordOption configures a DNS record added by DomainClient.
+type DNSRecordOption func(*s90api.DNSRecord)
 
-var dnsRecordDefaults = []dnsRecordOption{
-	DNSRecordTTL(30 * time.Second),
+var dnsRecordDefaults = []DNSRecordOption{
+	DNSRecordTTL(DefaultTTL),
 }
 
 // DNSRecordTTL sets the TTL of the DNS record.
-func DNSRecordTTL(duration time.Duration) dnsRecordOption {
+func DNSRecordTTL(duration time.Duration) DNSRecordOption {
 	return func(rec *s90api.DNSRecord) {
 		rec.TTL = duration
 	}
 }
 
 // DNSRecordPriority sets the priority of the DNS record.
-func DNSRecordPriority(priority int) dnsRecordOption {
+// Without it, the priority is 0.
+func DNSRecordPriority(priority int) DNSRecordOption {
 	return func(rec *s90api.DNSRecord) {
 		rec.Priority = priority
 	}
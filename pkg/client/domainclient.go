@@ -1,8 +1,10 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	s90api "barglvojtech.net/systems90api/pkg/embi"
 )
@@ -10,24 +12,130 @@ import (
 var (
 	// ErrDNSRecordNotFound is returned when the DNS record is not found.
 	ErrDNSRecordNotFound = errors.New("dns record not found")
+	// ErrDNSRecordLocked is returned for locked DNS records, which the API refuses to delete.
+	ErrDNSRecordLocked = errors.New("dns record is locked")
 )
 
 // DomainClient is a client for a specific domain.
 type DomainClient struct {
-	api      *s90api.Systems90Api
-	sid      string
-	domainID string
+	session       *session
+	domainID      string
+	zone          string
+	conflictCheck bool
 }
 
-func (dc *DomainClient) sessionDomain() s90api.SessionDomain {
+// Zone returns the name of the domain.
+func (dc *DomainClient) Zone() string {
+	return dc.zone
+}
+
+func (dc *DomainClient) sessionDomain(sid string) s90api.SessionDomain {
 	return s90api.SessionDomain{
-		SID:      dc.sid,
+		SID:      sid,
 		DomainID: dc.domainID,
 	}
 }
 
+func (dc *DomainClient) listDNS(ctx context.Context) ([]DNSRecord, error) {
+	var records []DNSRecord
+	err := dc.session.do(ctx, func(sid string) (err error) {
+		records, err = dc.session.api.ListDNSContext(ctx, dc.sessionDomain(sid))
+		return err
+	})
+	return records, err
+}
+
+func (dc *DomainClient) addDNS(ctx context.Context, rec *DNSRecord) (string, error) {
+	var id string
+	err := dc.session.do(ctx, func(sid string) (err error) {
+		id, err = dc.session.api.AddDNSContext(ctx, dc.sessionDomain(sid), rec)
+		return err
+	})
+	return id, err
+}
+
+// validateDNS validates the record and, unless disabled by WithoutConflictCheck, also against
+// the current records of the zone, ignoring the record with the replaced ID.
+func (dc *DomainClient) validateDNS(ctx context.Context, rec *DNSRecord, replacedID string) error {
+	if err := rec.Validate(); err != nil || !dc.conflictCheck {
+		return err
+	}
+
+	existing, err := dc.listDNS(ctx)
+	if err != nil {
+		return err
+	}
+	existing = slices.DeleteFunc(existing, func(r DNSRecord) bool {
+		return r.ID == replacedID
+	})
+	return rec.ValidateIn(existing)
+}
+
+// updateDNS adds the new record and deletes the old one like Systems90Api.UpdateDNS,
+// but retries every API call on its own, so a retry never adds the record twice.
+func (dc *DomainClient) updateDNS(ctx context.Context, id string, rec *DNSRecord) (string, error) {
+	newID, err := dc.addDNS(ctx, rec)
+	if err != nil {
+		return "", err
+	}
+
+	if err := dc.deleteDNS(ctx, id); err != nil {
+		// Roll back even when ctx is already done, otherwise the zone keeps both records.
+		if rbErr := dc.deleteDNS(context.WithoutCancel(ctx), newID); rbErr != nil {
+			return "", errors.Join(err, fmt.Errorf("systems90: rollback of record %s failed: %w", newID, rbErr))
+		}
+		return "", err
+	}
+
+	return newID, nil
+}
+
+func (dc *DomainClient) deleteDNS(ctx context.Context, id string) error {
+	return dc.session.do(ctx, func(sid string) error {
+		return dc.session.api.DeleteDNSContext(ctx, sid, id)
+	})
+}
+
+// ListDNSRecords returns all DNS records of the domain.
+func (dc *DomainClient) ListDNSRecords() ([]DNSRecord, error) {
+	return dc.ListDNSRecordsContext(context.Background())
+}
+
+// ListDNSRecordsContext is like ListDNSRecords but uses the given context for the request.
+func (dc *DomainClient) ListDNSRecordsContext(ctx context.Context) ([]DNSRecord, error) {
+	return dc.listDNS(ctx)
+}
+
 // AddDNSRecord adds a DNS record.
-func (dc *DomainClient) AddDNSRecord(name, value string, typ DNSType, options ...dnsRecordOption) (dnsID string, err error) {
+// The record is validated by DNSRecord.ValidateIn against the records of the zone before,
+// or only by DNSRecord.Validate with WithoutConflictCheck.
+func (dc *DomainClient) AddDNSRecord(name, value string, typ DNSType, options ...DNSRecordOption) (dnsID string, err error) {
+	return dc.AddDNSRecordContext(context.Background(), name, value, typ, options...)
+}
+
+// AddDNSRecordContext is like AddDNSRecord but uses the given context for the request.
+func (dc *DomainClient) AddDNSRecordContext(ctx context.Context, name, value string, typ DNSType, options ...DNSRecordOption) (dnsID string, err error) {
+	rec := &s90api.DNSRecord{
+		Name: name,
+		Type: typ,
+		IP:   value,
+	}
+
+	applyDNSRecordOptions(rec, options)
+	if err := dc.validateDNS(ctx, rec, ""); err != nil {
+		return "", err
+	}
+	return dc.addDNS(ctx, rec)
+}
+
+// UpdateDNSRecord replaces the DNS record with the given ID and returns the ID of the new record.
+// The new record is validated like by AddDNSRecord.
+func (dc *DomainClient) UpdateDNSRecord(id, name, value string, typ DNSType, options ...DNSRecordOption) (dnsID string, err error) {
+	return dc.UpdateDNSRecordContext(context.Background(), id, name, value, typ, options...)
+}
+
+// UpdateDNSRecordContext is like UpdateDNSRecord but uses the given context for the requests.
+func (dc *DomainClient) UpdateDNSRecordContext(ctx context.Context, id, name, value string, typ DNSType, options ...DNSRecordOption) (dnsID string, err error) {
 	rec := &s90api.DNSRecord{
 		Name: name,
 		Type: typ,
@@ -35,17 +143,35 @@ func (dc *DomainClient) AddDNSRecord(name, value string, typ DNSType, options ..
 	}
 
 	applyDNSRecordOptions(rec, options)
-	return dc.api.AddDNS(dc.sessionDomain(), rec)
+	if err := dc.validateDNS(ctx, rec, id); err != nil {
+		return "", err
+	}
+	return dc.updateDNS(ctx, id, rec)
 }
 
 // RemoveDNSRecord removes a DNS record.
 func (dc *DomainClient) RemoveDNSRecordByID(id string) error {
-	return dc.api.DeleteDNS(dc.sid, id)
+	return dc.RemoveDNSRecordByIDContext(context.Background(), id)
+}
+
+// RemoveDNSRecordByIDContext is like RemoveDNSRecordByID but uses the given context for the request.
+func (dc *DomainClient) RemoveDNSRecordByIDContext(ctx context.Context, id string) error {
+	return dc.deleteDNS(ctx, id)
 }
 
 // RemoveDNSRecordByName removes a DNS record.
+//
+// Deprecated: With several records of the name, it removes the last one listed regardless of type.
+// Use RemoveDNSRecords with FilterName and FilterType instead.
 func (dc *DomainClient) RemoveDNSRecordByName(name string) error {
-	dnsRecords, err := dc.api.ListDNS(dc.sessionDomain())
+	return dc.RemoveDNSRecordByNameContext(context.Background(), name)
+}
+
+// RemoveDNSRecordByNameContext is like RemoveDNSRecordByName but uses the given context for the requests.
+//
+// Deprecated: Use RemoveDNSRecordsContext with FilterName and FilterType instead.
+func (dc *DomainClient) RemoveDNSRecordByNameContext(ctx context.Context, name string) error {
+	dnsRecords, err := dc.listDNS(ctx)
 	if err != nil {
 		return err
 	}
@@ -61,5 +187,5 @@ func (dc *DomainClient) RemoveDNSRecordByName(name string) error {
 		return fmt.Errorf("systems90: %w (%s)", ErrDNSRecordNotFound, name)
 	}
 
-	return dc.api.DeleteDNS(dc.sid, rec.ID)
+	return dc.deleteDNS(ctx, rec.ID)
 }
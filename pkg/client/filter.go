@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// RecordFilter selects DNS records for FindRecords and RemoveDNSRecords.
+// Several filters select the records matching all of them.
+type RecordFilter func(*DNSRecord) bool
+
+// FilterName selects records with the name, relative to the zone ("" for the apex).
+// Names are compared case-insensitively.
+func FilterName(name string) RecordFilter {
+	return func(rec *DNSRecord) bool {
+		return strings.EqualFold(rec.Name, name)
+	}
+}
+
+// FilterNameWildcard selects records with names matching the pattern in the syntax of path.Match,
+// where * and ? do not match across labels, e.g. "*.dev" or "_acme-challenge.*".
+// Names are compared case-insensitively.
+func FilterNameWildcard(pattern string) RecordFilter {
+	pattern = strings.ReplaceAll(strings.ToLower(pattern), ".", "/")
+	return func(rec *DNSRecord) bool {
+		ok, _ := path.Match(pattern, strings.ReplaceAll(strings.ToLower(rec.Name), ".", "/"))
+		return ok
+	}
+}
+
+// FilterNameSuffix selects records with the name or a name below it, e.g. "dev" selects
+// "dev", "www.dev" and "a.b.dev" but not "mydev". An empty suffix selects all records.
+func FilterNameSuffix(suffix string) RecordFilter {
+	suffix = strings.ToLower(suffix)
+	return func(rec *DNSRecord) bool {
+		name := strings.ToLower(rec.Name)
+		return suffix == "" || name == suffix || strings.HasSuffix(name, "."+suffix)
+	}
+}
+
+// FilterType selects records of any of the types.
+func FilterType(types ...DNSType) RecordFilter {
+	return func(rec *DNSRecord) bool {
+		for _, typ := range types {
+			if rec.Type == typ {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterValue selects records with the value, as in DNSRecord.IP.
+func FilterValue(value string) RecordFilter {
+	return func(rec *DNSRecord) bool {
+		return rec.IP == value
+	}
+}
+
+// FilterLocked selects records by their locked state.
+func FilterLocked(locked bool) RecordFilter {
+	return func(rec *DNSRecord) bool {
+		return rec.Locked == locked
+	}
+}
+
+// FilterRecords returns the records matching all filters.
+func FilterRecords(records []DNSRecord, filters ...RecordFilter) []DNSRecord {
+	var matched []DNSRecord
+	for i := range records {
+		if matchFilters(&records[i], filters) {
+			matched = append(matched, records[i])
+		}
+	}
+	return matched
+}
+
+func matchFilters(rec *DNSRecord, filters []RecordFilter) bool {
+	for _, filter := range filters {
+		if !filter(rec) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindRecords returns all DNS records of the domain matching all filters.
+func (dc *DomainClient) FindRecords(filters ...RecordFilter) ([]DNSRecord, error) {
+	return dc.FindRecordsContext(context.Background(), filters...)
+}
+
+// FindRecordsContext is like FindRecords but uses the given context for the request.
+func (dc *DomainClient) FindRecordsContext(ctx context.Context, filters ...RecordFilter) ([]DNSRecord, error) {
+	records, err := dc.listDNS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return FilterRecords(records, filters...), nil
+}
+
+// RemoveResult reports the outcome of RemoveDNSRecords per record.
+type RemoveResult struct {
+	Removed []DNSRecord
+	Failed  []*RecordError
+}
+
+// RemoveDNSRecords removes all DNS records of the domain matching all filters.
+// At least one filter is required. Matched locked records are not sent to the API, which refuses
+// to delete them; they are reported as failed with ErrDNSRecordLocked. Use FilterLocked(false) to skip them.
+// When removing some records fails, the result lists them and the returned error joins their errors.
+func (dc *DomainClient) RemoveDNSRecords(filters ...RecordFilter) (*RemoveResult, error) {
+	return dc.RemoveDNSRecordsContext(context.Background(), filters...)
+}
+
+// RemoveDNSRecordsContext is like RemoveDNSRecords but uses the given context for the requests.
+func (dc *DomainClient) RemoveDNSRecordsContext(ctx context.Context, filters ...RecordFilter) (*RemoveResult, error) {
+	if len(filters) == 0 {
+		return nil, errors.New("systems90: RemoveDNSRecords needs at least one filter")
+	}
+
+	records, err := dc.FindRecordsContext(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RemoveResult{}
+	var errs []error
+	for _, rec := range records {
+		err := ErrDNSRecordLocked
+		if !rec.Locked {
+			err = dc.deleteDNS(ctx, rec.ID)
+		}
+		if err != nil {
+			recErr := &RecordError{Record: rec, Err: err}
+			result.Failed = append(result.Failed, recErr)
+			errs = append(errs, recErr)
+			continue
+		}
+		result.Removed = append(result.Removed, rec)
+	}
+
+	return result, errors.Join(errs...)
+}
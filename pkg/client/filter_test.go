@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestFilterRecords(t *testing.T) {
+	records := []DNSRecord{
+		{ID: "1", Name: "", Type: DNSTypeNS, IP: "ns.example.com.", Locked: true},
+		{ID: "2", Name: "www", Type: DNSTypeA, IP: "192.0.2.1"},
+		{ID: "3", Name: "www", Type: DNSTypeAAAA, IP: "2001:db8::1"},
+		{ID: "4", Name: "api.dev", Type: DNSTypeA, IP: "192.0.2.1"},
+		{ID: "5", Name: "a.b.dev", Type: DNSTypeA, IP: "192.0.2.2"},
+		{ID: "6", Name: "mydev", Type: DNSTypeA, IP: "192.0.2.3"},
+		{ID: "7", Name: "_acme-challenge.WWW", Type: DNSTypeTXT, IP: "token"},
+	}
+
+	params := []struct {
+		name     string
+		filters  []RecordFilter
+		expected []string
+	}{
+		{name: "none", expected: []string{"1", "2", "3", "4", "5", "6", "7"}},
+		{name: "apex", filters: []RecordFilter{FilterName("")}, expected: []string{"1"}},
+		{name: "exact name", filters: []RecordFilter{FilterName("www")}, expected: []string{"2", "3"}},
+		{name: "name and type", filters: []RecordFilter{FilterName("www"), FilterType(DNSTypeA)}, expected: []string{"2"}},
+		{name: "wildcard", filters: []RecordFilter{FilterNameWildcard("*.dev")}, expected: []string{"4"}},
+		{name: "wildcard label", filters: []RecordFilter{FilterNameWildcard("_acme-challenge.*")}, expected: []string{"7"}},
+		{name: "suffix", filters: []RecordFilter{FilterNameSuffix("dev")}, expected: []string{"4", "5"}},
+		{name: "types", filters: []RecordFilter{FilterType(DNSTypeAAAA, DNSTypeTXT)}, expected: []string{"3", "7"}},
+		{name: "value", filters: []RecordFilter{FilterValue("192.0.2.1")}, expected: []string{"2", "4"}},
+		{name: "locked", filters: []RecordFilter{FilterLocked(true)}, expected: []string{"1"}},
+		{name: "no match", filters: []RecordFilter{FilterName("www"), FilterType(DNSTypeMX)}},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			var got []string
+			for _, rec := range FilterRecords(records, p.filters...) {
+				got = append(got, rec.ID)
+			}
+			if !slices.Equal(got, p.expected) {
+				t.Errorf("got %v, expected %v", got, p.expected)
+			}
+		})
+	}
+}
+
+func TestRemoveDNSRecords(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "AAAA", IP: "2001:db8::1"})
+	lockedID := srv.AddRecord("example.com", s90test.Record{Name: "a.www", TTL: "60", Type: "A", IP: "192.0.2.2", Locked: true})
+	keptID := srv.AddRecord("example.com", s90test.Record{Name: "mail", TTL: "60", Type: "A", IP: "192.0.2.3"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	if _, err := dc.RemoveDNSRecords(); err == nil {
+		t.Errorf("got nil, expected error without filters")
+	}
+
+	result, err := dc.RemoveDNSRecords(FilterNameSuffix("www"), FilterType(DNSTypeA, DNSTypeAAAA))
+	if !errors.Is(err, ErrDNSRecordLocked) {
+		t.Errorf("got %v, expected %s for the locked record", err, ErrDNSRecordLocked)
+	}
+	if len(result.Removed) != 2 || len(result.Failed) != 1 || result.Failed[0].Record.ID != lockedID {
+		t.Errorf("got %+v, expected two removed records and the locked one failed", result)
+	}
+
+	records, err := dc.FindRecords(FilterLocked(false))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(records) != 1 || records[0].ID != keptID || records[0].TTL != time.Minute {
+		t.Errorf("got %+v, expected only record %s", records, keptID)
+	}
+}
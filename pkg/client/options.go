@@ -0,0 +1,107 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	s90api "barglvojtech.net/systems90api/pkg/embi"
+)
+
+type clientConfig struct {
+	apiOptions  []s90api.Option
+	lazyLogin   bool
+	store       SessionStore
+	idleTimeout time.Duration
+
+	noConflictCheck bool
+}
+
+// Option configures a Client created by NewClient.
+type Option func(*clientConfig)
+
+// WithAPIOptions passes the options to the underlying Systems90Api.
+func WithAPIOptions(options ...s90api.Option) Option {
+	return func(cfg *clientConfig) {
+		cfg.apiOptions = append(cfg.apiOptions, options...)
+	}
+}
+
+// WithBaseURL sets the URL of the API, e.g. a staging host or a local stand-in.
+func WithBaseURL(baseURL string) Option {
+	return WithAPIOptions(s90api.WithBaseURL(baseURL))
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) Option {
+	return WithAPIOptions(s90api.WithHTTPClient(client))
+}
+
+// WithTransport sets the transport of the HTTP client.
+func WithTransport(transport http.RoundTripper) Option {
+	return WithAPIOptions(s90api.WithTransport(transport))
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return WithAPIOptions(s90api.WithUserAgent(userAgent))
+}
+
+// WithHeader sets a header sent with every request.
+func WithHeader(key, value string) Option {
+	return WithAPIOptions(s90api.WithHeader(key, value))
+}
+
+// WithRetryPolicy sets the policy for retrying requests after transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return WithAPIOptions(s90api.WithRetryPolicy(policy))
+}
+
+// WithRateLimiter makes every request wait for the limiter.
+// The limiter is shared by all DomainClients of the client.
+func WithRateLimiter(limiter Limiter) Option {
+	return WithAPIOptions(s90api.WithRateLimiter(limiter))
+}
+
+// WithStrictParsing makes listing records fail when the TTL or priority of a record cannot be parsed.
+func WithStrictParsing() Option {
+	return WithAPIOptions(s90api.WithStrictParsing())
+}
+
+// WithParseWarningHandler sets a function called for every field of a listed record
+// which could not be parsed when strict parsing is not enabled.
+func WithParseWarningHandler(handler func(*RecordParseError)) Option {
+	return WithAPIOptions(s90api.WithParseWarningHandler(handler))
+}
+
+// WithLazyLogin defers the login from NewClient to the first request.
+func WithLazyLogin() Option {
+	return func(cfg *clientConfig) {
+		cfg.lazyLogin = true
+	}
+}
+
+// WithSessionStore makes the client reuse a session ID kept in the store
+// and save new session IDs to it. Close then keeps the session alive
+// for other users of the store; Logout invalidates it.
+func WithSessionStore(store SessionStore) Option {
+	return func(cfg *clientConfig) {
+		cfg.store = store
+	}
+}
+
+// WithIdleLogout makes the client log out after the given time without requests.
+// The next request logs in again.
+func WithIdleLogout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) {
+		cfg.idleTimeout = timeout
+	}
+}
+
+// WithoutConflictCheck makes AddDNSRecord and UpdateDNSRecord validate records only
+// on their own by DNSRecord.Validate. By default, they also list the records of the zone
+// and check the record against them by DNSRecord.ValidateIn, which costs a request per call.
+func WithoutConflictCheck() Option {
+	return func(cfg *clientConfig) {
+		cfg.noConflictCheck = true
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	s90api "barglvojtech.net/systems90api/pkg/embi"
+)
+
+// session is a login shared by a Client and all DomainClients created by it.
+// It logs in on first use and again when the API reports the session as expired.
+type session struct {
+	api         *s90api.Systems90Api
+	cred        s90api.Credentials
+	store       SessionStore
+	idleTimeout time.Duration
+
+	mu        sync.Mutex
+	sid       string
+	idleTimer *time.Timer
+}
+
+// acquire returns the session ID, logging in or loading it from the store when there is none.
+func (s *session) acquire(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.touch()
+	if s.sid != "" {
+		return s.sid, nil
+	}
+
+	if s.store != nil {
+		sid, err := s.store.Load(s.cred.UID)
+		if err != nil {
+			return "", err
+		}
+		if sid != "" {
+			s.sid = sid
+			return sid, nil
+		}
+	}
+
+	return s.login(ctx)
+}
+
+// relogin replaces the expired session ID, unless another call already did so.
+func (s *session) relogin(ctx context.Context, expiredSID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sid != expiredSID && s.sid != "" {
+		return s.sid, nil
+	}
+	return s.login(ctx)
+}
+
+func (s *session) login(ctx context.Context) (string, error) {
+	sid, err := s.api.LoginContext(ctx, s.cred)
+	if err != nil {
+		return "", err
+	}
+
+	s.sid = sid
+	if s.store != nil {
+		if err := s.store.Save(s.cred.UID, sid); err != nil {
+			return "", err
+		}
+	}
+	return sid, nil
+}
+
+// touch restarts the idle timer.
+func (s *session) touch() {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	if s.idleTimer == nil {
+		s.idleTimer = time.AfterFunc(s.idleTimeout, s.idleLogout)
+		return
+	}
+	s.idleTimer.Reset(s.idleTimeout)
+}
+
+func (s *session) idleLogout() {
+	s.logout(context.Background())
+}
+
+// do calls fn with the session ID and calls it once more after logging in again
+// when the session expired. fn should make a single API call, as it may be repeated.
+func (s *session) do(ctx context.Context, fn func(sid string) error) error {
+	sid, err := s.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(sid)
+	if !errors.Is(err, s90api.ErrForbidden) || !s.expired(ctx, sid) {
+		return err
+	}
+
+	sid, loginErr := s.relogin(ctx, sid)
+	if loginErr != nil {
+		return errors.Join(err, loginErr)
+	}
+	return fn(sid)
+}
+
+// expired reports whether the session ID is no longer valid. The API refuses locked records
+// and domains of other accounts with Forbidden too, so the session is checked by listing domains.
+func (s *session) expired(ctx context.Context, sid string) bool {
+	_, err := s.api.ListDomainsContext(ctx, sid)
+	return errors.Is(err, s90api.ErrSessionExpired)
+}
+
+// logout invalidates the session and removes it from the store.
+func (s *session) logout(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopTimer()
+	if s.sid == "" && s.store != nil {
+		sid, err := s.store.Load(s.cred.UID)
+		if err != nil {
+			return err
+		}
+		s.sid = sid
+	}
+	if s.sid == "" {
+		return nil
+	}
+
+	err := s.api.LogoutContext(ctx, s.sid)
+	s.sid = ""
+	if s.store != nil {
+		err = errors.Join(err, s.store.Delete(s.cred.UID))
+	}
+	return err
+}
+
+// close ends the use of the session. With a store, the session is kept alive for its other users.
+func (s *session) close(ctx context.Context) error {
+	if s.store == nil {
+		return s.logout(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopTimer()
+	return nil
+}
+
+func (s *session) stopTimer() {
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SessionStore keeps session IDs per UID, so they can be reused by other clients.
+type SessionStore interface {
+	// Load returns the stored session ID of the UID, or an empty string when there is none.
+	Load(uid string) (sid string, err error)
+	// Save stores the session ID of the UID.
+	Save(uid, sid string) error
+	// Delete removes the session ID of the UID.
+	Delete(uid string) error
+}
+
+// MemorySessionStore is a SessionStore sharing sessions between clients of a process.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]string
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]string),
+	}
+}
+
+func (s *MemorySessionStore) Load(uid string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessions[uid], nil
+}
+
+func (s *MemorySessionStore) Save(uid, sid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[uid] = sid
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, uid)
+	return nil
+}
+
+// FileSessionStore is a SessionStore keeping sessions in a JSON file,
+// so they can be reused across processes, e.g. CLI invocations.
+type FileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSessionStore creates a FileSessionStore backed by the file at path.
+// The file and its directory are created on the first Save.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{
+		path: path,
+	}
+}
+
+func (s *FileSessionStore) Load(uid string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return sessions[uid], nil
+}
+
+func (s *FileSessionStore) Save(uid, sid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	sessions[uid] = sid
+	return s.write(sessions)
+}
+
+func (s *FileSessionStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := sessions[uid]; !ok {
+		return nil
+	}
+
+	delete(sessions, uid)
+	return s.write(sessions)
+}
+
+func (s *FileSessionStore) read() (map[string]string, error) {
+	sessions := make(map[string]string)
+
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return sessions, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// write replaces the file atomically, readable only by its owner.
+func (s *FileSessionStore) write(sessions map[string]string) error {
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -6,9 +6,63 @@ import (
 
 type Credentials = s90api.Credentials
 type DNSRecord = s90api.DNSRecord
+type Domain = s90api.Domain
 
 type DNSType = s90api.DNSType
 
+const PriorityUnset = s90api.PriorityUnset
+
+// DNSTypeFromString returns the DNSType of the name, see s90api.DNSTypeFromString.
+func DNSTypeFromString(s string) DNSType {
+	return s90api.DNSTypeFromString(s)
+}
+
+type RData = s90api.RData
+type RDataA = s90api.RDataA
+type RDataAAAA = s90api.RDataAAAA
+type RDataCNAME = s90api.RDataCNAME
+type RDataDNAME = s90api.RDataDNAME
+type RDataNS = s90api.RDataNS
+type RDataMX = s90api.RDataMX
+type RDataSRV = s90api.RDataSRV
+type RDataSSHFP = s90api.RDataSSHFP
+type RDataTXT = s90api.RDataTXT
+type RDataCAA = s90api.RDataCAA
+type RDataLOC = s90api.RDataLOC
+type RDataPTR = s90api.RDataPTR
+type RDataTLSA = s90api.RDataTLSA
+type RDataSVCB = s90api.RDataSVCB
+type RDataHTTPS = s90api.RDataHTTPS
+type RDataNAPTR = s90api.RDataNAPTR
+type RDataDS = s90api.RDataDS
+
+type APIError = s90api.APIError
+type StatusCode = s90api.StatusCode
+
+type RecordParseError = s90api.RecordParseError
+type ParseErrors = s90api.ParseErrors
+
+type FieldError = s90api.FieldError
+type ValidationError = s90api.ValidationError
+
+type RetryPolicy = s90api.RetryPolicy
+type Limiter = s90api.Limiter
+
+const (
+	StatusOK         = s90api.StatusOK
+	StatusBadRequest = s90api.StatusBadRequest
+	StatusForbidden  = s90api.StatusForbidden
+)
+
+var (
+	ErrInvalidSession = s90api.ErrInvalidSession
+	ErrForbidden      = s90api.ErrForbidden
+	ErrBadRequest     = s90api.ErrBadRequest
+	ErrSessionExpired = s90api.ErrSessionExpired
+	ErrRateLimited    = s90api.ErrRateLimited
+	ErrInvalidRecord  = s90api.ErrInvalidRecord
+)
+
 const (
 	DNSTypeA     = s90api.DNSTypeA
 	DNSTypeAAAA  = s90api.DNSTypeAAAA
@@ -21,4 +75,10 @@ const (
 	DNSTypeSSHFP = s90api.DNSTypeSSHFP
 	DNSTypeTXT   = s90api.DNSTypeTXT
 	DNSTypeCAA   = s90api.DNSTypeCAA
+	DNSTypePTR   = s90api.DNSTypePTR
+	DNSTypeTLSA  = s90api.DNSTypeTLSA
+	DNSTypeSVCB  = s90api.DNSTypeSVCB
+	DNSTypeHTTPS = s90api.DNSTypeHTTPS
+	DNSTypeNAPTR = s90api.DNSTypeNAPTR
+	DNSTypeDS    = s90api.DNSTypeDS
 )
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"barglvojtech.net/systems90api/internal/zonefile"
+)
+
+// ParseZone reads records of a BIND-style master file for the given zone.
+// Owner names are returned relative to the zone, the apex as an empty name.
+func ParseZone(r io.Reader, zone string) ([]DNSRecord, error) {
+	return zonefile.Parse(r, zone)
+}
+
+// ExportZone writes all DNS records of the domain as a BIND-style master file.
+func (dc *DomainClient) ExportZone(w io.Writer) error {
+	return dc.ExportZoneContext(context.Background(), w)
+}
+
+// ExportZoneContext is like ExportZone but uses the given context for the request.
+func (dc *DomainClient) ExportZoneContext(ctx context.Context, w io.Writer) error {
+	records, err := dc.listDNS(ctx)
+	if err != nil {
+		return err
+	}
+
+	return zonefile.Write(w, dc.zone, records)
+}
+
+// ImportResult reports the outcome of ImportZone per record.
+type ImportResult struct {
+	Added   []DNSRecord
+	Skipped []DNSRecord // Skipped records already existed in the zone.
+	Failed  []*RecordError
+}
+
+// RecordError is an error of an operation on a single DNS record.
+type RecordError struct {
+	Record DNSRecord
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("systems90: %s record %s: %s", e.Record.Type, e.Record.Name, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// ImportZone reads a BIND-style master file and adds its records to the domain.
+// Records already present in the zone are skipped. When adding some records fails,
+// the result lists them and the returned error joins their errors.
+func (dc *DomainClient) ImportZone(r io.Reader) (*ImportResult, error) {
+	return dc.ImportZoneContext(context.Background(), r)
+}
+
+// ImportZoneContext is like ImportZone but uses the given context for the requests.
+func (dc *DomainClient) ImportZoneContext(ctx context.Context, r io.Reader) (*ImportResult, error) {
+	records, err := ParseZone(r, dc.zone)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := dc.listDNS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	var errs []error
+	for _, rec := range records {
+		if slices.ContainsFunc(existing, func(have DNSRecord) bool { return sameRecordData(have, rec) }) {
+			result.Skipped = append(result.Skipped, rec)
+			continue
+		}
+
+		id, err := dc.addDNS(ctx, &rec)
+		if err != nil {
+			recErr := &RecordError{Record: rec, Err: err}
+			result.Failed = append(result.Failed, recErr)
+			errs = append(errs, recErr)
+			continue
+		}
+
+		rec.ID = id
+		result.Added = append(result.Added, rec)
+		existing = append(existing, rec)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// normalizeTarget returns the record with its target host name lowercased and absolute,
+// as the API may store targets without the trailing dot that the zone file has.
+func normalizeTarget(rec DNSRecord) DNSRecord {
+	switch rec.Type {
+	case DNSTypeCNAME, DNSTypeDNAME, DNSTypeNS, DNSTypePTR, DNSTypeMX:
+		rec.IP = absName(rec.IP)
+	case DNSTypeSRV:
+		// SRV values are "weight port target".
+		if fields := strings.Fields(rec.IP); len(fields) == 3 {
+			fields[2] = absName(fields[2])
+			rec.IP = strings.Join(fields, " ")
+		}
+	}
+	return rec
+}
+
+func absName(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
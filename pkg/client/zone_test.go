@@ -0,0 +1,47 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestImportZone(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	// The API stores targets as entered, here without the trailing dot.
+	srv.AddRecord("example.com", s90test.Record{Name: "", TTL: "3600", Type: "MX", IP: "MX.example.com", Priority: "10"})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "3600", Type: "CNAME", IP: "example.net"})
+	srv.AddRecord("example.com", s90test.Record{Name: "_sip._tcp", TTL: "3600", Type: "SRV", IP: "5 5060 sip.example.com", Priority: "20"})
+
+	c, err := NewClient(Credentials{UID: "user", Password: "secret"}, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	defer c.Close()
+
+	dc, err := c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	var zone strings.Builder
+	if err := dc.ExportZone(&zone); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	result, err := dc.ImportZone(strings.NewReader(zone.String()))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(result.Added) != 0 || len(result.Skipped) != 3 {
+		t.Errorf("got %+v, expected all records skipped", result)
+	}
+	if got := srv.Records("example.com"); len(got) != 3 {
+		t.Errorf("got %+v, expected no duplicates", got)
+	}
+}
@@ -1,6 +1,7 @@
 package embi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,32 +12,41 @@ import (
 	"barglvojtech.net/systems90api/internal/types"
 )
 
-var (
-	ErrInvalidSession = errors.New("to use this endpoint, you need to be logged in")
-)
-
 // Systems90Api is a basic interface for communication with the Systems90 API.
 type Systems90Api struct {
 	client         *http.Client
 	requestBuilder func(*request.Builder)
+	strict         bool
+	warn           func(*RecordParseError)
 }
 
 // NewSystems90Api creates a new Systems90Api.
-func NewSystems90Api() *Systems90Api {
+func NewSystems90Api(options ...Option) *Systems90Api {
+	cfg := newConfig(options)
+
 	return &Systems90Api{
-		client: &http.Client{},
+		client: cfg.client,
+		strict: cfg.strict,
+		warn:   cfg.warn,
 		requestBuilder: func(b *request.Builder) {
-			b.Url("https://admin.systems90.cz/api/")
-			b.Header(http.Header{
-				"Content-Type": []string{"application/x-www-form-urlencoded"},
-			})
+			b.Url(cfg.baseURL)
+			b.Header(cfg.header.Clone())
+			b.Retry(cfg.retry)
+			if cfg.limiter != nil {
+				b.Limit(cfg.limiter)
+			}
 		},
 	}
 }
 
 // Login logs in to the API and returns a session ID.
 func (api *Systems90Api) Login(cred Credentials) (SID string, err error) {
-	resp, err := request.Fetch[types.LoginResponse](api.client, func(b *request.Builder) {
+	return api.LoginContext(context.Background(), cred)
+}
+
+// LoginContext is like Login but uses the given context for the request.
+func (api *Systems90Api) LoginContext(ctx context.Context, cred Credentials) (SID string, err error) {
+	resp, err := request.Fetch[types.LoginResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Method(http.MethodPost)
 		b.Path("login")
@@ -48,9 +58,9 @@ func (api *Systems90Api) Login(cred Credentials) (SID string, err error) {
 
 	switch {
 	case err != nil && resp != nil:
-		return "", fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return "", newAPIError("login", err, &resp.Status)
 	case err != nil:
-		return "", err
+		return "", newAPIError("login", err, nil)
 	}
 
 	return resp.SID, nil
@@ -58,13 +68,19 @@ func (api *Systems90Api) Login(cred Credentials) (SID string, err error) {
 
 // Logout invalidates the session ID.
 func (api *Systems90Api) Logout(SID string) error {
+	return api.LogoutContext(context.Background(), SID)
+}
+
+// LogoutContext is like Logout but uses the given context for the request.
+func (api *Systems90Api) LogoutContext(ctx context.Context, SID string) error {
 	if isInvalidSession(SID) {
 		return ErrInvalidSession
 	}
 
-	resp, err := request.Fetch[types.LogoutResponse](api.client, func(b *request.Builder) {
+	resp, err := request.Fetch[types.LogoutResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Path("logout")
+		b.Idempotent()
 		b.UrlParams(types.LogoutRequest{
 			SID: SID,
 		})
@@ -72,9 +88,9 @@ func (api *Systems90Api) Logout(SID string) error {
 
 	switch {
 	case err != nil && resp != nil:
-		return fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return newAPIError("logout", err, &resp.Status)
 	case err != nil:
-		return err
+		return newAPIError("logout", err, nil)
 	}
 
 	return nil
@@ -82,13 +98,19 @@ func (api *Systems90Api) Logout(SID string) error {
 
 // ListDomains lists all domains registred for logged in UID.
 func (api *Systems90Api) ListDomains(SID string) ([]Domain, error) {
+	return api.ListDomainsContext(context.Background(), SID)
+}
+
+// ListDomainsContext is like ListDomains but uses the given context for the request.
+func (api *Systems90Api) ListDomainsContext(ctx context.Context, SID string) ([]Domain, error) {
 	if isInvalidSession(SID) {
 		return nil, ErrInvalidSession
 	}
 
-	resp, err := request.Fetch[types.ListDomainsResponse](api.client, func(b *request.Builder) {
+	resp, err := request.Fetch[types.ListDomainsResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Path("domain_list")
+		b.Idempotent()
 		b.Method(http.MethodGet)
 		b.UrlParams(types.ListDomainsRequest{
 			SID: SID,
@@ -97,9 +119,9 @@ func (api *Systems90Api) ListDomains(SID string) ([]Domain, error) {
 
 	switch {
 	case err != nil && resp != nil:
-		return nil, fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return nil, newAPIError("domain_list", err, &resp.Status)
 	case err != nil:
-		return nil, err
+		return nil, newAPIError("domain_list", err, nil)
 	}
 
 	domains := make([]Domain, len(resp.Domains.Domains))
@@ -114,13 +136,19 @@ func (api *Systems90Api) ListDomains(SID string) ([]Domain, error) {
 
 // ListDNS lists all DNS records for the given domain.
 func (api *Systems90Api) ListDNS(sd SessionDomain) ([]DNSRecord, error) {
+	return api.ListDNSContext(context.Background(), sd)
+}
+
+// ListDNSContext is like ListDNS but uses the given context for the request.
+func (api *Systems90Api) ListDNSContext(ctx context.Context, sd SessionDomain) ([]DNSRecord, error) {
 	if isInvalidSession(sd) {
 		return nil, ErrInvalidSession
 	}
 
-	resp, err := request.Fetch[types.ListDnsResponse](api.client, func(b *request.Builder) {
+	resp, err := request.Fetch[types.ListDnsResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Path("domain_list_dns")
+		b.Idempotent()
 		b.Method(http.MethodGet)
 		b.UrlParams(types.ListDnsRequest{
 			SID:      sd.SID,
@@ -130,21 +158,28 @@ func (api *Systems90Api) ListDNS(sd SessionDomain) ([]DNSRecord, error) {
 
 	switch {
 	case err != nil && resp != nil:
-		return nil, fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return nil, newAPIError("domain_list_dns", err, &resp.Status)
 	case err != nil:
-		return nil, err
+		return nil, newAPIError("domain_list_dns", err, nil)
 	}
 
+	var parseErrs ParseErrors
 	records := make([]DNSRecord, len(resp.Zone.Records))
 	for i, rec := range resp.Zone.Records {
-		ttl, err := strconv.ParseUint(rec.TTL, 10, 64)
+		ttl, err := strconv.ParseUint(rec.TTL, 10, 32)
 		if err != nil {
+			parseErrs = append(parseErrs, &RecordParseError{ID: rec.DnsID, Field: "TTL", Value: rec.TTL, Err: err})
 			ttl = 0
 		}
 
-		priority, err := strconv.ParseUint(rec.Priority, 10, 64)
-		if err != nil {
-			priority = 0
+		priority := PriorityUnset
+		if rec.Priority != "" {
+			p, err := strconv.ParseUint(rec.Priority, 10, 16)
+			if err != nil {
+				parseErrs = append(parseErrs, &RecordParseError{ID: rec.DnsID, Field: "Priority", Value: rec.Priority, Err: err})
+			} else {
+				priority = int(p)
+			}
 		}
 
 		records[i] = DNSRecord{
@@ -153,20 +188,42 @@ func (api *Systems90Api) ListDNS(sd SessionDomain) ([]DNSRecord, error) {
 			TTL:      time.Duration(ttl) * time.Second,
 			Type:     DNSTypeFromString(rec.Type),
 			IP:       rec.IP,
-			Priority: int(priority),
+			Priority: priority,
 			Locked:   rec.Locked,
 		}
+
+		// Values the API accepted but which cannot be parsed are kept only in IP.
+		if data, err := ParseRData(records[i].Type, rec.IP, priority); err == nil {
+			records[i].Data = data
+		}
+	}
+
+	if len(parseErrs) != 0 {
+		if api.strict {
+			return nil, parseErrs
+		}
+		if api.warn != nil {
+			for _, err := range parseErrs {
+				api.warn(err)
+			}
+		}
 	}
 	return records, nil
 }
 
 // AddDNS adds a new DNS record to the given domain.
 func (api *Systems90Api) AddDNS(sd SessionDomain, dnsRecord *DNSRecord) (string, error) {
+	return api.AddDNSContext(context.Background(), sd, dnsRecord)
+}
+
+// AddDNSContext is like AddDNS but uses the given context for the request.
+func (api *Systems90Api) AddDNSContext(ctx context.Context, sd SessionDomain, dnsRecord *DNSRecord) (string, error) {
 	if isInvalidSession(sd) {
 		return "", ErrInvalidSession
 	}
 
-	resp, err := request.Fetch[types.AddDnsResponse](api.client, func(b *request.Builder) {
+	typ, value, priority := dnsRecord.wire()
+	resp, err := request.Fetch[types.AddDnsResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Path("domain_add_dns")
 		b.Method(http.MethodPost)
@@ -177,17 +234,17 @@ func (api *Systems90Api) AddDNS(sd SessionDomain, dnsRecord *DNSRecord) (string,
 		b.Payload(types.AddDnsRequest_Payload{
 			Name:     dnsRecord.Name,
 			TTL:      strconv.FormatUint(uint64(dnsRecord.TTL.Seconds()), 10),
-			Type:     dnsRecord.Type.String(),
-			IP:       dnsRecord.IP,
-			Priority: strconv.FormatInt(int64(dnsRecord.Priority), 10),
+			Type:     typ.String(),
+			IP:       value,
+			Priority: formatPriority(priority),
 		})
 	})
 
 	switch {
 	case err != nil && resp != nil:
-		return "", fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return "", newAPIError("domain_add_dns", err, &resp.Status)
 	case err != nil:
-		return "", err
+		return "", newAPIError("domain_add_dns", err, nil)
 	}
 
 	return resp.DNSID, nil
@@ -195,11 +252,16 @@ func (api *Systems90Api) AddDNS(sd SessionDomain, dnsRecord *DNSRecord) (string,
 
 // DeleteDNS deletes a DNS record from the given domain.
 func (api *Systems90Api) DeleteDNS(sid string, dnsRecordId string) error {
+	return api.DeleteDNSContext(context.Background(), sid, dnsRecordId)
+}
+
+// DeleteDNSContext is like DeleteDNS but uses the given context for the request.
+func (api *Systems90Api) DeleteDNSContext(ctx context.Context, sid string, dnsRecordId string) error {
 	if isInvalidSession(sid) {
 		return ErrInvalidSession
 	}
 
-	resp, err := request.Fetch[types.DeleteDnsResponse](api.client, func(b *request.Builder) {
+	resp, err := request.Fetch[types.DeleteDnsResponse](ctx, api.client, func(b *request.Builder) {
 		api.requestBuilder(b)
 		b.Path("domain_delete_dns")
 		b.Method(http.MethodGet)
@@ -211,10 +273,46 @@ func (api *Systems90Api) DeleteDNS(sid string, dnsRecordId string) error {
 
 	switch {
 	case err != nil && resp != nil:
-		return fmt.Errorf("systems90: %w: %s", err, resp.Status.Text)
+		return newAPIError("domain_delete_dns", err, &resp.Status)
 	case err != nil:
-		return err
+		return newAPIError("domain_delete_dns", err, nil)
 	}
 
 	return nil
 }
+
+// UpdateDNS replaces the DNS record with the given ID and returns the ID of the new record.
+// The API has no endpoint to edit a record, so the new record is added first and the old one
+// is deleted afterwards. When the deletion fails, the added record is deleted again.
+func (api *Systems90Api) UpdateDNS(sd SessionDomain, dnsRecordId string, dnsRecord *DNSRecord) (string, error) {
+	return api.UpdateDNSContext(context.Background(), sd, dnsRecordId, dnsRecord)
+}
+
+// UpdateDNSContext is like UpdateDNS but uses the given context for the requests.
+func (api *Systems90Api) UpdateDNSContext(ctx context.Context, sd SessionDomain, dnsRecordId string, dnsRecord *DNSRecord) (string, error) {
+	if isInvalidSession(sd) {
+		return "", ErrInvalidSession
+	}
+
+	newID, err := api.AddDNSContext(ctx, sd, dnsRecord)
+	if err != nil {
+		return "", err
+	}
+
+	if err := api.DeleteDNSContext(ctx, sd.SID, dnsRecordId); err != nil {
+		// Roll back even when ctx is already done, otherwise the zone keeps both records.
+		if rbErr := api.DeleteDNSContext(context.WithoutCancel(ctx), sd.SID, newID); rbErr != nil {
+			return "", errors.Join(err, fmt.Errorf("systems90: rollback of record %s failed: %w", newID, rbErr))
+		}
+		return "", err
+	}
+
+	return newID, nil
+}
+
+func formatPriority(priority int) string {
+	if priority == PriorityUnset {
+		return ""
+	}
+	return strconv.Itoa(priority)
+}
@@ -0,0 +1,230 @@
+package embi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func newTestApi(t *testing.T) (*Systems90Api, *s90test.Server) {
+	t.Helper()
+
+	srv := s90test.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	return NewSystems90Api(WithBaseURL(srv.URL)), srv
+}
+
+func TestLogin(t *testing.T) {
+	api, srv := newTestApi(t)
+
+	_, err := api.Login(Credentials{UID: "user", Password: "wrong"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("got %v, expected %s", err, ErrForbidden)
+	}
+	if errors.Is(err, ErrSessionExpired) {
+		t.Errorf("got %v, expected login error not to match %s", err, ErrSessionExpired)
+	}
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if sid == "" {
+		t.Fatalf("got empty session ID")
+	}
+	if got := srv.Sessions(); got != 1 {
+		t.Errorf("got %d sessions, expected 1", got)
+	}
+
+	if err := api.Logout(sid); err != nil {
+		t.Errorf("got %s, expected nil", err)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected 0", got)
+	}
+}
+
+func TestDNSRecords(t *testing.T) {
+	api, srv := newTestApi(t)
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	domains, err := api.ListDomains(sid)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(domains) != 1 || domains[0].Zone != "example.com" {
+		t.Fatalf("got %v, expected only example.com", domains)
+	}
+
+	sd := SessionDomain{SID: sid, DomainID: domains[0].DomainID}
+	id, err := api.AddDNS(sd, &DNSRecord{
+		Name:     "mail",
+		TTL:      time.Hour,
+		Type:     DNSTypeMX,
+		IP:       "mx.example.com",
+		Priority: 10,
+	})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	stored := srv.Records("example.com")
+	if len(stored) != 1 || stored[0].TTL != "3600" || stored[0].Priority != "10" {
+		t.Fatalf("got %+v, expected single MX record", stored)
+	}
+
+	records, err := api.ListDNS(sd)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	expected := DNSRecord{ID: id, Name: "mail", TTL: time.Hour, Type: DNSTypeMX, IP: "mx.example.com", Priority: 10,
+		Data: RDataMX{Preference: 10, Host: "mx.example.com"}}
+	if len(records) != 1 || records[0] != expected {
+		t.Fatalf("got %+v, expected %+v", records, expected)
+	}
+
+	if err := api.DeleteDNS(sid, id); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Records("example.com"); len(got) != 0 {
+		t.Errorf("got %+v, expected no records", got)
+	}
+}
+
+func TestForeignDomain(t *testing.T) {
+	api, srv := newTestApi(t)
+	foreignID := srv.AddDomain("other", "example.org")
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	_, err = api.ListDNS(SessionDomain{SID: sid, DomainID: foreignID})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, expected *APIError", err)
+	}
+	if apiErr.Endpoint != "domain_list_dns" || apiErr.Code != StatusForbidden {
+		t.Errorf("got %+v, expected Forbidden from domain_list_dns", apiErr)
+	}
+	if strings.Contains(apiErr.URL, sid) {
+		t.Errorf("got %s, expected URL without session ID", apiErr.URL)
+	}
+}
+
+func TestStatusInBody(t *testing.T) {
+	api, srv := newTestApi(t)
+	srv.BodyStatusOnly = true
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "wrong"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("got %v, expected %s", err, ErrForbidden)
+	}
+	if sid != "" {
+		t.Errorf("got %s, expected empty session ID", sid)
+	}
+
+	_, err = api.ListDomains("expired")
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("got %v, expected %s", err, ErrSessionExpired)
+	}
+}
+
+func TestUpdateDNS(t *testing.T) {
+	api, srv := newTestApi(t)
+	oldID := srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	lockedID := srv.AddRecord("example.com", s90test.Record{Name: "ns", TTL: "60", Type: "A", IP: "192.0.2.2", Locked: true})
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	domains, err := api.ListDomains(sid)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	sd := SessionDomain{SID: sid, DomainID: domains[0].DomainID}
+
+	newID, err := api.UpdateDNS(sd, oldID, &DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.3"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if newID == oldID {
+		t.Errorf("got %s, expected new record ID", newID)
+	}
+
+	_, err = api.UpdateDNS(sd, lockedID, &DNSRecord{Name: "ns", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.4"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("got %v, expected %s", err, ErrForbidden)
+	}
+	if errors.Is(err, ErrSessionExpired) {
+		t.Errorf("got %v, expected locked record not to match %s", err, ErrSessionExpired)
+	}
+
+	records := srv.Records("example.com")
+	if len(records) != 2 || records[0].ID != lockedID || records[1].ID != newID || records[1].IP != "192.0.2.3" {
+		t.Errorf("got %+v, expected locked record and updated record", records)
+	}
+}
+
+func TestListDNSParseErrors(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	goodID := srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	ttlID := srv.AddRecord("example.com", s90test.Record{Name: "ttl", TTL: "soon", Type: "A", IP: "192.0.2.2"})
+	prioID := srv.AddRecord("example.com", s90test.Record{Name: "", TTL: "60", Type: "MX", IP: "mx.example.com.", Priority: "high"})
+
+	list := func(options ...Option) ([]DNSRecord, error) {
+		api := NewSystems90Api(append(options, WithBaseURL(srv.URL))...)
+		sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+		if err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+		domains, err := api.ListDomains(sid)
+		if err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+		return api.ListDNS(SessionDomain{SID: sid, DomainID: domains[0].DomainID})
+	}
+
+	var warnings []*RecordParseError
+	records, err := list(WithParseWarningHandler(func(err *RecordParseError) {
+		warnings = append(warnings, err)
+	}))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(records) != 3 || records[0].Priority != PriorityUnset || records[1].TTL != 0 || records[2].Priority != PriorityUnset {
+		t.Errorf("got %+v, expected zero TTL and unset priorities", records)
+	}
+	if len(warnings) != 2 || warnings[0].ID != ttlID || warnings[0].Field != "TTL" ||
+		warnings[1].ID != prioID || warnings[1].Field != "Priority" {
+		t.Errorf("got %v, expected warnings of records %s and %s", warnings, ttlID, prioID)
+	}
+
+	_, err = list(WithStrictParsing())
+	var parseErrs ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 2 {
+		t.Fatalf("got %v, expected parse errors", err)
+	}
+	var recErr *RecordParseError
+	if !errors.As(err, &recErr) || recErr.ID == goodID {
+		t.Errorf("got %v, expected error of a corrupt record", recErr)
+	}
+}
@@ -0,0 +1,128 @@
+package embi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"barglvojtech.net/systems90api/internal/request"
+	"barglvojtech.net/systems90api/internal/types"
+)
+
+var (
+	ErrInvalidSession = errors.New("to use this endpoint, you need to be logged in")
+
+	// ErrForbidden matches an APIError with the Forbidden status.
+	ErrForbidden = errors.New("forbidden")
+	// ErrBadRequest matches an APIError with the Bad request status.
+	ErrBadRequest = errors.New("bad request")
+	// ErrSessionExpired matches an APIError with the Forbidden status of domain_list or logout,
+	// whose only reason to refuse a request is an invalid session. Other endpoints are refused
+	// with Forbidden also for locked records or domains of other accounts, so their errors
+	// match only ErrForbidden.
+	ErrSessionExpired = errors.New("session expired")
+)
+
+const (
+	StatusOK         = StatusCode(types.StatusOk)         // StatusOK is a status for successful request
+	StatusBadRequest = StatusCode(types.StatusBadRequest) // StatusBadRequest is a status for bad request
+	StatusForbidden  = StatusCode(types.StatusForbidden)  // StatusForbidden is a status for forbidden request
+)
+
+// StatusCode is a status reported by the Systems90 API in the response body.
+type StatusCode string
+
+// APIError is returned when the Systems90 API refuses a request.
+type APIError struct {
+	Endpoint   string     // Endpoint is the name of the called endpoint, e.g. "domain_list".
+	HTTPStatus int        // HTTPStatus is the HTTP status code of the response.
+	Code       StatusCode // Code is the status reported in the response body, if any.
+	Text       string     // Text is the status text reported in the response body, if any.
+	URL        string     // URL is the requested URL without the session ID.
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("systems90: %s failed with status %d %s", e.Endpoint, e.HTTPStatus, http.StatusText(e.HTTPStatus))
+	if e.Code != "" {
+		msg += fmt.Sprintf(": %s", e.Code)
+	}
+	if e.Text != "" && e.Text != string(e.Code) {
+		msg += fmt.Sprintf(": %s", e.Text)
+	}
+	return msg
+}
+
+// Is reports whether the error matches ErrForbidden, ErrBadRequest or ErrSessionExpired.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrForbidden:
+		return e.forbidden()
+	case ErrBadRequest:
+		return e.Code == StatusBadRequest || (e.Code == "" && e.HTTPStatus == http.StatusBadRequest)
+	case ErrSessionExpired:
+		return (e.Endpoint == "domain_list" || e.Endpoint == "logout") && e.forbidden()
+	}
+	return false
+}
+
+func (e *APIError) forbidden() bool {
+	return e.Code == StatusForbidden || (e.Code == "" && e.HTTPStatus == http.StatusForbidden)
+}
+
+// RecordParseError reports a field of a record listed by ListDNS which could not be parsed.
+type RecordParseError struct {
+	ID    string // ID is the ID of the DNS record.
+	Field string // Field is the name of the DNSRecord field, e.g. "TTL".
+	Value string // Value is the value sent by the API.
+	Err   error
+}
+
+func (e *RecordParseError) Error() string {
+	return fmt.Sprintf("systems90: record %s: invalid %s %q: %s", e.ID, e.Field, e.Value, e.Err)
+}
+
+func (e *RecordParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors is returned by ListDNS with strict parsing when fields of some records
+// could not be parsed.
+type ParseErrors []*RecordParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of the single fields.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// newAPIError converts an error returned by request.Fetch into an APIError.
+// Errors not caused by the response, e.g. network errors, are returned unchanged.
+func newAPIError(endpoint string, err error, status *types.Status) error {
+	var statusErr *request.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		HTTPStatus: statusErr.StatusCode,
+		URL:        statusErr.URL.String(),
+	}
+	if status != nil {
+		apiErr.Code = StatusCode(status.Code)
+		apiErr.Text = status.Text
+	}
+	return apiErr
+}
@@ -0,0 +1,128 @@
+package embi
+
+import (
+	"net/http"
+
+	"barglvojtech.net/systems90api/internal/request"
+)
+
+// DefaultBaseURL is the URL of the Systems90 API used when no other is given.
+const DefaultBaseURL = "https://admin.systems90.cz/api/"
+
+type config struct {
+	baseURL   string
+	client    *http.Client
+	transport http.RoundTripper
+	header    http.Header
+	retry     RetryPolicy
+	limiter   Limiter
+	strict    bool
+	warn      func(*RecordParseError)
+}
+
+// Option configures a Systems90Api created by NewSystems90Api.
+type Option func(*config)
+
+func newConfig(options []Option) *config {
+	cfg := &config{
+		baseURL: DefaultBaseURL,
+		client:  &http.Client{},
+		retry:   DefaultRetryPolicy,
+		header: http.Header{
+			"Content-Type": []string{"application/x-www-form-urlencoded"},
+		},
+	}
+
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	if cfg.transport != nil {
+		client := *cfg.client
+		client.Transport = cfg.transport
+		cfg.client = &client
+	}
+
+	return cfg
+}
+
+// WithBaseURL sets the URL of the API, e.g. a staging host or a local stand-in.
+func WithBaseURL(baseURL string) Option {
+	return func(cfg *config) {
+		cfg.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *config) {
+		if client != nil {
+			cfg.client = client
+		}
+	}
+}
+
+// WithTransport sets the transport of the HTTP client.
+// The client given by WithHTTPClient is copied, not modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cfg *config) {
+		cfg.transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader sets a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(cfg *config) {
+		cfg.header.Set(key, value)
+	}
+}
+
+// RetryPolicy configures retries of requests that failed with a transient error,
+// i.e. a connection error, a timeout, 429 Too Many Requests or a 5xx status.
+// The Retry-After header of the response takes precedence over the computed delay.
+//
+// Only logout, domain_list and domain_list_dns are retried unless RetryNonIdempotent is set,
+// which allows login, domain_add_dns and domain_delete_dns to be sent more than once.
+// The zero value disables retries.
+type RetryPolicy = request.RetryPolicy
+
+// DefaultRetryPolicy is used when no other is given. It makes up to three attempts
+// with an exponential backoff starting at half a second.
+var DefaultRetryPolicy = request.DefaultRetryPolicy
+
+// WithRetryPolicy sets the policy for retrying requests after transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(cfg *config) {
+		cfg.retry = policy
+	}
+}
+
+// WithRateLimiter makes every request, including retries, wait for the limiter.
+// The limiter may be shared with other Systems90Api instances, e.g. of the same account.
+func WithRateLimiter(limiter Limiter) Option {
+	return func(cfg *config) {
+		cfg.limiter = limiter
+	}
+}
+
+// WithStrictParsing makes ListDNS fail with ParseErrors when the TTL or priority
+// of a record cannot be parsed. By default, such fields are left as zero TTL
+// and PriorityUnset and reported to the handler given by WithParseWarningHandler.
+func WithStrictParsing() Option {
+	return func(cfg *config) {
+		cfg.strict = true
+	}
+}
+
+// WithParseWarningHandler sets a function called by ListDNS for every field
+// which could not be parsed when strict parsing is not enabled.
+func WithParseWarningHandler(handler func(*RecordParseError)) Option {
+	return func(cfg *config) {
+		cfg.warn = handler
+	}
+}
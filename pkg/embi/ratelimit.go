@@ -0,0 +1,160 @@
+package embi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a request is refused by the rate limiter,
+// either at once in fail-fast mode or because the context deadline would pass while waiting.
+var ErrRateLimited = errors.New("systems90: rate limit exceeded")
+
+// Limiter delays or refuses requests to the API, see WithRateLimiter.
+type Limiter interface {
+	// Wait blocks until a request to the endpoint, e.g. "domain_list", may be sent.
+	Wait(ctx context.Context, endpoint string) error
+}
+
+// RateLimit is a rate of requests per second with a burst of requests allowed at once.
+// A zero Rate means no limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter is a token bucket Limiter with a limit for all requests
+// and optional limits for single endpoints. A request has to pass both.
+//
+// It is safe for concurrent use and can be shared by several Systems90Api instances.
+type RateLimiter struct {
+	mu        sync.Mutex
+	all       *bucket
+	endpoints map[string]*bucket
+	failFast  bool
+}
+
+// RateLimiterOption configures a RateLimiter created by NewRateLimiter.
+type RateLimiterOption func(*RateLimiter)
+
+// NewRateLimiter creates a RateLimiter limiting all requests to the limit.
+func NewRateLimiter(limit RateLimit, options ...RateLimiterOption) *RateLimiter {
+	l := &RateLimiter{
+		all:       newBucket(limit),
+		endpoints: make(map[string]*bucket),
+	}
+
+	for _, opt := range options {
+		opt(l)
+	}
+
+	return l
+}
+
+// WithEndpointLimit limits requests to the endpoint, e.g. "domain_add_dns".
+func WithEndpointLimit(endpoint string, limit RateLimit) RateLimiterOption {
+	return func(l *RateLimiter) {
+		if b := newBucket(limit); b != nil {
+			l.endpoints[endpoint] = b
+		}
+	}
+}
+
+// WithFailFast makes Wait return ErrRateLimited instead of blocking when the limit is reached.
+func WithFailFast() RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.failFast = true
+	}
+}
+
+// Wait blocks until a request to the endpoint is allowed by all limits.
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	l.mu.Lock()
+
+	buckets := make([]*bucket, 0, 2)
+	if l.all != nil {
+		buckets = append(buckets, l.all)
+	}
+	if b := l.endpoints[endpoint]; b != nil {
+		buckets = append(buckets, b)
+	}
+
+	now := time.Now()
+	var delay time.Duration
+	for _, b := range buckets {
+		b.advance(now)
+		delay = max(delay, b.delay())
+	}
+
+	if delay > 0 && l.failFast {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRateLimited, endpoint)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s: %w", ErrRateLimited, endpoint, context.DeadlineExceeded)
+	}
+
+	// Tokens are taken in advance, so concurrent callers queue up behind each other.
+	for _, b := range buckets {
+		b.tokens--
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for _, b := range buckets {
+			b.tokens = min(b.tokens+1, float64(b.limit.Burst))
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(limit RateLimit) *bucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &bucket{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+	}
+}
+
+// advance adds the tokens accumulated since the last call.
+func (b *bucket) advance(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+		b.tokens = min(b.tokens, float64(b.limit.Burst))
+	}
+	b.last = now
+}
+
+// delay returns the time until a token is available.
+func (b *bucket) delay() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
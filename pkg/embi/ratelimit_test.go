@@ -0,0 +1,83 @@
+package embi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	l := NewRateLimiter(RateLimit{Rate: 1, Burst: 2},
+		WithEndpointLimit("domain_add_dns", RateLimit{Rate: 1, Burst: 1}),
+		WithFailFast(),
+	)
+
+	params := []struct {
+		endpoint string
+		err      error
+	}{
+		{endpoint: "domain_add_dns"},
+		{endpoint: "domain_add_dns", err: ErrRateLimited},
+		{endpoint: "domain_list"},
+		{endpoint: "domain_list", err: ErrRateLimited},
+	}
+
+	for i, p := range params {
+		if err := l.Wait(ctx, p.endpoint); !errors.Is(err, p.err) {
+			t.Errorf("%d: got %v, expected %v", i, err, p.err)
+		}
+	}
+}
+
+func TestRateLimiterBlocking(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Rate: 20, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background(), "domain_list"); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("got %s, expected at least 100ms for 3 requests at 20/s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	l = NewRateLimiter(RateLimit{Rate: 0.1, Burst: 1})
+	l.Wait(ctx, "domain_list")
+	if err := l.Wait(ctx, "domain_list"); !errors.Is(err, ErrRateLimited) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, expected rate limit with deadline exceeded", err)
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	l := NewRateLimiter(RateLimit{}, WithEndpointLimit("domain_list", RateLimit{Rate: 0.1, Burst: 1}), WithFailFast())
+	api := NewSystems90Api(WithBaseURL(srv.URL), WithRateLimiter(l))
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if _, err := api.ListDomains(sid); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if _, err := api.ListDomains(sid); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got %v, expected %s", err, ErrRateLimited)
+	}
+	if err := api.Logout(sid); err != nil {
+		t.Errorf("got %s, expected nil", err)
+	}
+}
@@ -0,0 +1,591 @@
+package embi
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// RData is the typed value of a DNS record of one DNSType.
+//
+// The API sends the value of every record as a single string in the ip field,
+// with the priority of MX and SRV records in a separate field. ParseRData and
+// DNSRecord.SetData convert between the two.
+type RData interface {
+	// Type returns the DNS type of the record.
+	Type() DNSType
+	// wire returns the value and priority as sent to the API.
+	wire() (value string, priority int)
+}
+
+// RDataA is the value of an A record.
+type RDataA struct {
+	Addr netip.Addr
+}
+
+// RDataAAAA is the value of an AAAA record.
+type RDataAAAA struct {
+	Addr netip.Addr
+}
+
+// RDataCNAME is the value of a CNAME record.
+type RDataCNAME struct {
+	Target string
+}
+
+// RDataDNAME is the value of a DNAME record.
+type RDataDNAME struct {
+	Target string
+}
+
+// RDataNS is the value of an NS record.
+type RDataNS struct {
+	Host string
+}
+
+// RDataMX is the value of an MX record.
+type RDataMX struct {
+	Preference uint16
+	Host       string
+}
+
+// RDataSRV is the value of an SRV record.
+type RDataSRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// RDataSSHFP is the value of an SSHFP record.
+// The fingerprint is kept hex encoded, so records stay comparable.
+type RDataSSHFP struct {
+	Algorithm   uint8
+	FPType      uint8
+	Fingerprint string
+}
+
+// RDataTXT is the value of a TXT record.
+type RDataTXT struct {
+	Text string
+}
+
+// RDataCAA is the value of a CAA record.
+type RDataCAA struct {
+	Flags uint8
+	Tag   string
+	Value string
+}
+
+// RDataLOC is the value of a LOC record (RFC 1876).
+// Latitude and Longitude are in degrees, positive to the north and east,
+// the other fields are in meters.
+type RDataLOC struct {
+	Latitude            float64
+	Longitude           float64
+	Altitude            float64
+	Size                float64
+	HorizontalPrecision float64
+	VerticalPrecision   float64
+}
+
+// RDataPTR is the value of a PTR record.
+type RDataPTR struct {
+	Target string
+}
+
+// RDataTLSA is the value of a TLSA record.
+// The certificate association data is kept hex encoded.
+type RDataTLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	Certificate  string
+}
+
+// RDataSVCB is the value of an SVCB record (RFC 9460).
+// Params holds the SvcParams in presentation format, e.g. "alpn=h2,h3 port=8443".
+type RDataSVCB struct {
+	Priority uint16
+	Target   string
+	Params   string
+}
+
+// RDataHTTPS is the value of an HTTPS record, which has the format of SVCB.
+type RDataHTTPS RDataSVCB
+
+// RDataNAPTR is the value of a NAPTR record (RFC 3403).
+type RDataNAPTR struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Services    string
+	Regexp      string
+	Replacement string
+}
+
+// RDataDS is the value of a DS record. The digest is kept hex encoded.
+type RDataDS struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	Digest     string
+}
+
+func (RDataA) Type() DNSType     { return DNSTypeA }
+func (RDataAAAA) Type() DNSType  { return DNSTypeAAAA }
+func (RDataCNAME) Type() DNSType { return DNSTypeCNAME }
+func (RDataDNAME) Type() DNSType { return DNSTypeDNAME }
+func (RDataNS) Type() DNSType    { return DNSTypeNS }
+func (RDataMX) Type() DNSType    { return DNSTypeMX }
+func (RDataSRV) Type() DNSType   { return DNSTypeSRV }
+func (RDataSSHFP) Type() DNSType { return DNSTypeSSHFP }
+func (RDataTXT) Type() DNSType   { return DNSTypeTXT }
+func (RDataCAA) Type() DNSType   { return DNSTypeCAA }
+func (RDataLOC) Type() DNSType   { return DNSTypeLOC }
+func (RDataPTR) Type() DNSType   { return DNSTypePTR }
+func (RDataTLSA) Type() DNSType  { return DNSTypeTLSA }
+func (RDataSVCB) Type() DNSType  { return DNSTypeSVCB }
+func (RDataHTTPS) Type() DNSType { return DNSTypeHTTPS }
+func (RDataNAPTR) Type() DNSType { return DNSTypeNAPTR }
+func (RDataDS) Type() DNSType    { return DNSTypeDS }
+
+func (d RDataA) wire() (string, int)     { return d.Addr.String(), 0 }
+func (d RDataAAAA) wire() (string, int)  { return d.Addr.String(), 0 }
+func (d RDataCNAME) wire() (string, int) { return d.Target, 0 }
+func (d RDataDNAME) wire() (string, int) { return d.Target, 0 }
+func (d RDataNS) wire() (string, int)    { return d.Host, 0 }
+func (d RDataMX) wire() (string, int)    { return d.Host, int(d.Preference) }
+func (d RDataTXT) wire() (string, int)   { return d.Text, 0 }
+func (d RDataPTR) wire() (string, int)   { return d.Target, 0 }
+func (d RDataHTTPS) wire() (string, int) { return RDataSVCB(d).wire() }
+
+func (d RDataTLSA) wire() (string, int) {
+	return fmt.Sprintf("%d %d %d %s", d.Usage, d.Selector, d.MatchingType, d.Certificate), 0
+}
+
+func (d RDataSVCB) wire() (string, int) {
+	if d.Params == "" {
+		return fmt.Sprintf("%d %s", d.Priority, d.Target), 0
+	}
+	return fmt.Sprintf("%d %s %s", d.Priority, d.Target, d.Params), 0
+}
+
+func (d RDataNAPTR) wire() (string, int) {
+	return fmt.Sprintf("%d %d %s %s %s %s", d.Order, d.Preference,
+		strconv.Quote(d.Flags), strconv.Quote(d.Services), strconv.Quote(d.Regexp), d.Replacement), 0
+}
+
+func (d RDataDS) wire() (string, int) {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest), 0
+}
+
+func (d RDataSRV) wire() (string, int) {
+	return fmt.Sprintf("%d %d %s", d.Weight, d.Port, d.Target), int(d.Priority)
+}
+
+func (d RDataSSHFP) wire() (string, int) {
+	return fmt.Sprintf("%d %d %s", d.Algorithm, d.FPType, d.Fingerprint), 0
+}
+
+func (d RDataCAA) wire() (string, int) {
+	return fmt.Sprintf("%d %s %s", d.Flags, d.Tag, strconv.Quote(d.Value)), 0
+}
+
+func (d RDataLOC) wire() (string, int) {
+	return fmt.Sprintf("%s %s %.2fm %.2fm %.2fm %.2fm",
+		formatCoordinate(d.Latitude, "N", "S"),
+		formatCoordinate(d.Longitude, "E", "W"),
+		d.Altitude, d.Size, d.HorizontalPrecision, d.VerticalPrecision,
+	), 0
+}
+
+// ParseRData parses the value and priority of a record as sent by the API.
+func ParseRData(typ DNSType, value string, priority int) (RData, error) {
+	data, err := parseRData(typ, value, priority)
+	if err != nil {
+		return nil, fmt.Errorf("systems90: invalid %s value %q: %w", typ, value, err)
+	}
+	return data, nil
+}
+
+func parseRData(typ DNSType, value string, priority int) (RData, error) {
+	switch typ {
+	case DNSTypeA:
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.Is4() {
+			return nil, errors.New("not an IPv4 address")
+		}
+		return RDataA{Addr: addr}, nil
+	case DNSTypeAAAA:
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		if !addr.Is6() || addr.Is4In6() {
+			return nil, errors.New("not an IPv6 address")
+		}
+		return RDataAAAA{Addr: addr}, nil
+	case DNSTypeCNAME, DNSTypeDNAME, DNSTypeNS, DNSTypeMX, DNSTypePTR:
+		fields := strings.Fields(value)
+		if len(fields) != 1 {
+			return nil, errors.New("expected a single domain name")
+		}
+		return parseNameRData(typ, fields[0], priority)
+	case DNSTypeSRV:
+		return parseSRV(value, priority)
+	case DNSTypeSSHFP:
+		return parseSSHFP(value)
+	case DNSTypeTXT:
+		return RDataTXT{Text: value}, nil
+	case DNSTypeCAA:
+		return parseCAA(value)
+	case DNSTypeLOC:
+		return parseLOC(value)
+	case DNSTypeTLSA:
+		return parseTLSA(value)
+	case DNSTypeSVCB:
+		return parseSVCB(value)
+	case DNSTypeHTTPS:
+		data, err := parseSVCB(value)
+		return RDataHTTPS(data), err
+	case DNSTypeNAPTR:
+		return parseNAPTR(value)
+	case DNSTypeDS:
+		return parseDS(value)
+	default:
+		return nil, errors.New("unsupported type")
+	}
+}
+
+func parseNameRData(typ DNSType, name string, priority int) (RData, error) {
+	switch typ {
+	case DNSTypeCNAME:
+		return RDataCNAME{Target: name}, nil
+	case DNSTypeDNAME:
+		return RDataDNAME{Target: name}, nil
+	case DNSTypeNS:
+		return RDataNS{Host: name}, nil
+	case DNSTypePTR:
+		return RDataPTR{Target: name}, nil
+	default:
+		preference, err := toUint16(priority)
+		if err != nil {
+			return nil, err
+		}
+		return RDataMX{Preference: preference, Host: name}, nil
+	}
+}
+
+func parseSRV(value string, priority int) (RData, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 3 {
+		return nil, errors.New("expected weight, port and target")
+	}
+
+	prio, err := toUint16(priority)
+	if err != nil {
+		return nil, err
+	}
+	weight, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("weight: %w", err)
+	}
+	port, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("port: %w", err)
+	}
+
+	return RDataSRV{
+		Priority: prio,
+		Weight:   uint16(weight),
+		Port:     uint16(port),
+		Target:   fields[2],
+	}, nil
+}
+
+func parseSSHFP(value string) (RData, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return nil, errors.New("expected algorithm, fingerprint type and fingerprint")
+	}
+
+	algorithm, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("algorithm: %w", err)
+	}
+	fpType, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint type: %w", err)
+	}
+	fingerprint, err := parseHex(fields[2:])
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint: %w", err)
+	}
+
+	return RDataSSHFP{
+		Algorithm:   uint8(algorithm),
+		FPType:      uint8(fpType),
+		Fingerprint: fingerprint,
+	}, nil
+}
+
+func parseCAA(value string) (RData, error) {
+	flags, rest, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok {
+		return nil, errors.New("expected flags, tag and value")
+	}
+	tag, val, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok {
+		return nil, errors.New("expected flags, tag and value")
+	}
+
+	f, err := strconv.ParseUint(flags, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("flags: %w", err)
+	}
+
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, `"`) {
+		val, err = strconv.Unquote(val)
+		if err != nil {
+			return nil, fmt.Errorf("value: %w", err)
+		}
+	}
+
+	return RDataCAA{Flags: uint8(f), Tag: tag, Value: val}, nil
+}
+
+func parseTLSA(value string) (RData, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, errors.New("expected usage, selector, matching type and data")
+	}
+
+	var params [3]uint8
+	for i, name := range []string{"usage", "selector", "matching type"} {
+		v, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		params[i] = uint8(v)
+	}
+	cert, err := parseHex(fields[3:])
+	if err != nil {
+		return nil, fmt.Errorf("certificate data: %w", err)
+	}
+
+	return RDataTLSA{
+		Usage:        params[0],
+		Selector:     params[1],
+		MatchingType: params[2],
+		Certificate:  cert,
+	}, nil
+}
+
+func parseSVCB(value string) (RDataSVCB, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return RDataSVCB{}, errors.New("expected priority and target")
+	}
+
+	priority, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return RDataSVCB{}, fmt.Errorf("priority: %w", err)
+	}
+
+	return RDataSVCB{
+		Priority: uint16(priority),
+		Target:   fields[1],
+		Params:   strings.Join(fields[2:], " "),
+	}, nil
+}
+
+func parseNAPTR(value string) (RData, error) {
+	fields, err := splitQuoted(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 6 {
+		return nil, errors.New("expected order, preference, flags, services, regexp and replacement")
+	}
+
+	order, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("order: %w", err)
+	}
+	preference, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("preference: %w", err)
+	}
+
+	return RDataNAPTR{
+		Order:       uint16(order),
+		Preference:  uint16(preference),
+		Flags:       fields[2],
+		Services:    fields[3],
+		Regexp:      fields[4],
+		Replacement: fields[5],
+	}, nil
+}
+
+func parseDS(value string) (RData, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return nil, errors.New("expected key tag, algorithm, digest type and digest")
+	}
+
+	keyTag, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("key tag: %w", err)
+	}
+	algorithm, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("algorithm: %w", err)
+	}
+	digestType, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("digest type: %w", err)
+	}
+	digest, err := parseHex(fields[3:])
+	if err != nil {
+		return nil, fmt.Errorf("digest: %w", err)
+	}
+
+	return RDataDS{
+		KeyTag:     uint16(keyTag),
+		Algorithm:  uint8(algorithm),
+		DigestType: uint8(digestType),
+		Digest:     digest,
+	}, nil
+}
+
+// parseHex joins the fields of hex encoded data and returns it in upper case.
+func parseHex(fields []string) (string, error) {
+	data := strings.ToUpper(strings.Join(fields, ""))
+	if _, err := hex.DecodeString(data); err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+// splitQuoted splits the value at spaces, keeping quoted strings together and unquoting them.
+func splitQuoted(value string) ([]string, error) {
+	var fields []string
+	for value = strings.TrimSpace(value); value != ""; value = strings.TrimSpace(value) {
+		if value[0] != '"' {
+			field, rest, _ := strings.Cut(value, " ")
+			fields = append(fields, field)
+			value = rest
+			continue
+		}
+
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, err
+		}
+		field, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		value = value[len(quoted):]
+	}
+	return fields, nil
+}
+
+// parseLOC parses the LOC value in the RFC 1876 format:
+// d1 [m1 [s1]] {N|S} d2 [m2 [s2]] {E|W} alt[m] [siz[m] [hp[m] [vp[m]]]]
+func parseLOC(value string) (RData, error) {
+	fields := strings.Fields(value)
+
+	lat, fields, err := parseCoordinate(fields, "N", "S", 90)
+	if err != nil {
+		return nil, fmt.Errorf("latitude: %w", err)
+	}
+	long, fields, err := parseCoordinate(fields, "E", "W", 180)
+	if err != nil {
+		return nil, fmt.Errorf("longitude: %w", err)
+	}
+	if len(fields) == 0 || len(fields) > 4 {
+		return nil, errors.New("expected altitude and at most size and precisions")
+	}
+
+	// Defaults of RFC 1876 for the optional fields.
+	meters := []float64{0, 1, 10000, 10}
+	for i, field := range fields {
+		meters[i], err = strconv.ParseFloat(strings.TrimSuffix(field, "m"), 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return RDataLOC{
+		Latitude:            lat,
+		Longitude:           long,
+		Altitude:            meters[0],
+		Size:                meters[1],
+		HorizontalPrecision: meters[2],
+		VerticalPrecision:   meters[3],
+	}, nil
+}
+
+// parseCoordinate parses degrees, optional minutes and seconds and the hemisphere
+// from the fields and returns the remaining fields.
+func parseCoordinate(fields []string, positive, negative string, limit float64) (float64, []string, error) {
+	var parts []float64
+	for i, field := range fields {
+		if field == positive || field == negative {
+			if len(parts) == 0 {
+				return 0, nil, errors.New("missing degrees")
+			}
+
+			deg := parts[0]
+			for j, unit := range []float64{60, 3600}[:len(parts)-1] {
+				deg += parts[j+1] / unit
+			}
+			if deg > limit {
+				return 0, nil, fmt.Errorf("%g out of range", deg)
+			}
+			if field == negative {
+				deg = -deg
+			}
+			return deg, fields[i+1:], nil
+		}
+
+		if len(parts) == 3 {
+			break
+		}
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, nil, err
+		}
+		parts = append(parts, v)
+	}
+	return 0, nil, fmt.Errorf("missing %s or %s", positive, negative)
+}
+
+func formatCoordinate(deg float64, positive, negative string) string {
+	hemisphere := positive
+	if deg < 0 {
+		hemisphere, deg = negative, -deg
+	}
+
+	// Round to milliseconds of arc, the precision of the format.
+	ms := int64(math.Round(deg * 3600 * 1000))
+	return fmt.Sprintf("%d %d %d.%03d %s", ms/3600000, ms/60000%60, ms/1000%60, ms%1000, hemisphere)
+}
+
+func toUint16(priority int) (uint16, error) {
+	if priority < 0 || priority > math.MaxUint16 {
+		return 0, fmt.Errorf("priority %d out of range", priority)
+	}
+	return uint16(priority), nil
+}
@@ -0,0 +1,171 @@
+package embi
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestParseRData(t *testing.T) {
+	params := []struct {
+		typ      DNSType
+		value    string
+		priority int
+		expected RData
+		wire     string
+	}{
+		{typ: DNSTypeA, value: "192.0.2.1", expected: RDataA{Addr: netip.MustParseAddr("192.0.2.1")}},
+		{typ: DNSTypeAAAA, value: "2001:db8::1", expected: RDataAAAA{Addr: netip.MustParseAddr("2001:db8::1")}},
+		{typ: DNSTypeCNAME, value: "www.example.com.", expected: RDataCNAME{Target: "www.example.com."}},
+		{typ: DNSTypeDNAME, value: "example.net.", expected: RDataDNAME{Target: "example.net."}},
+		{typ: DNSTypeNS, value: "ns1.example.com.", expected: RDataNS{Host: "ns1.example.com."}},
+		{typ: DNSTypeMX, value: "mx.example.com.", priority: 10, expected: RDataMX{Preference: 10, Host: "mx.example.com."}},
+		{typ: DNSTypeSRV, value: "5 5060 sip.example.com.", priority: 10,
+			expected: RDataSRV{Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com."}},
+		{typ: DNSTypeSSHFP, value: "4 2 12ab34cd", expected: RDataSSHFP{Algorithm: 4, FPType: 2, Fingerprint: "12AB34CD"},
+			wire: "4 2 12AB34CD"},
+		{typ: DNSTypeTXT, value: "v=spf1 -all", expected: RDataTXT{Text: "v=spf1 -all"}},
+		{typ: DNSTypeCAA, value: `0 issue "letsencrypt.org"`, expected: RDataCAA{Flags: 0, Tag: "issue", Value: "letsencrypt.org"}},
+		{typ: DNSTypeCAA, value: "128 iodef mailto:ca@example.com",
+			expected: RDataCAA{Flags: 128, Tag: "iodef", Value: "mailto:ca@example.com"},
+			wire:     `128 iodef "mailto:ca@example.com"`},
+		{typ: DNSTypeLOC, value: "50 5 12.000 N 14 30 0.000 W 200.00m 1.00m 10000.00m 10.00m",
+			expected: RDataLOC{Latitude: 50.086666666666666, Longitude: -14.5, Altitude: 200, Size: 1, HorizontalPrecision: 10000, VerticalPrecision: 10}},
+		{typ: DNSTypeLOC, value: "50 S 14 30 E 10m", wire: "50 0 0.000 S 14 30 0.000 E 10.00m 1.00m 10000.00m 10.00m",
+			expected: RDataLOC{Latitude: -50, Longitude: 14.5, Altitude: 10, Size: 1, HorizontalPrecision: 10000, VerticalPrecision: 10}},
+		{typ: DNSTypePTR, value: "host.example.com.", expected: RDataPTR{Target: "host.example.com."}},
+		{typ: DNSTypeTLSA, value: "3 1 1 0c72ac70", expected: RDataTLSA{Usage: 3, Selector: 1, MatchingType: 1, Certificate: "0C72AC70"},
+			wire: "3 1 1 0C72AC70"},
+		{typ: DNSTypeSVCB, value: "1 svc.example.com. alpn=h2 port=8443",
+			expected: RDataSVCB{Priority: 1, Target: "svc.example.com.", Params: "alpn=h2 port=8443"}},
+		{typ: DNSTypeHTTPS, value: "0 example.com.", expected: RDataHTTPS{Priority: 0, Target: "example.com."}},
+		{typ: DNSTypeNAPTR, value: `100 10 "u" "E2U+sip" "!^.*$!sip:info@example.com!" .`,
+			expected: RDataNAPTR{Order: 100, Preference: 10, Flags: "u", Services: "E2U+sip", Regexp: "!^.*$!sip:info@example.com!", Replacement: "."}},
+		{typ: DNSTypeDS, value: "60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118",
+			expected: RDataDS{KeyTag: 60485, Algorithm: 5, DigestType: 1, Digest: "2BB183AF5F22588179A53B0A98631FAD1A292118"}},
+	}
+
+	for _, p := range params {
+		got, err := ParseRData(p.typ, p.value, p.priority)
+		if err != nil {
+			t.Errorf("%s %s: got %s, expected nil", p.typ, p.value, err)
+			continue
+		}
+		if got != p.expected {
+			t.Errorf("%s %s: got %+v, expected %+v", p.typ, p.value, got, p.expected)
+		}
+
+		wire := p.wire
+		if wire == "" {
+			wire = p.value
+		}
+		var rec DNSRecord
+		rec.SetData(got)
+		if rec.Type != p.typ || rec.IP != wire || rec.Priority != p.priority {
+			t.Errorf("%s %s: got %s %q %d, expected %s %q %d", p.typ, p.value, rec.Type, rec.IP, rec.Priority, p.typ, wire, p.priority)
+		}
+	}
+}
+
+func TestParseRDataErrors(t *testing.T) {
+	params := []struct {
+		typ      DNSType
+		value    string
+		priority int
+	}{
+		{typ: DNSTypeA, value: "2001:db8::1"},
+		{typ: DNSTypeAAAA, value: "192.0.2.1"},
+		{typ: DNSTypeCNAME, value: "a b"},
+		{typ: DNSTypeMX, value: "mx.example.com.", priority: 70000},
+		{typ: DNSTypeSRV, value: "5 sip.example.com."},
+		{typ: DNSTypeSSHFP, value: "4 2 xyz"},
+		{typ: DNSTypeCAA, value: "0 issue"},
+		{typ: DNSTypeLOC, value: "95 N 14 E 10m"},
+		{typ: DNSTypeLOC, value: "50 14 E 10m"},
+		{typ: DNSTypeTLSA, value: "3 1 x 0c72"},
+		{typ: DNSTypeNAPTR, value: `100 10 "u" "E2U+sip" .`},
+		{typ: "TYPE65280", value: "x"},
+	}
+
+	for _, p := range params {
+		if _, err := ParseRData(p.typ, p.value, p.priority); err == nil {
+			t.Errorf("%s %s: got nil, expected error", p.typ, p.value)
+		}
+	}
+}
+
+func TestAddDNSData(t *testing.T) {
+	api, srv := newTestApi(t)
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	domains, err := api.ListDomains(sid)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	sd := SessionDomain{SID: sid, DomainID: domains[0].DomainID}
+
+	data := RDataSRV{Priority: 10, Weight: 5, Port: 443, Target: "www.example.com."}
+	if _, err := api.AddDNS(sd, &DNSRecord{Name: "_https._tcp", TTL: time.Hour, Data: data}); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	stored := srv.Records("example.com")
+	if len(stored) != 1 || stored[0].Type != "SRV" || stored[0].IP != "5 443 www.example.com." || stored[0].Priority != "10" {
+		t.Fatalf("got %+v, expected single SRV record", stored)
+	}
+
+	records, err := api.ListDNS(sd)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(records) != 1 || records[0].Data != data {
+		t.Errorf("got %+v, expected data %+v", records, data)
+	}
+}
+
+func TestDNSTypeFromString(t *testing.T) {
+	params := []struct {
+		given    string
+		expected DNSType
+		known    bool
+	}{
+		{given: "A", expected: DNSTypeA, known: true},
+		{given: "https", expected: DNSTypeHTTPS, known: true},
+		{given: "TYPE65280", expected: "TYPE65280"},
+		{given: "", expected: ""},
+	}
+
+	for _, p := range params {
+		got := DNSTypeFromString(p.given)
+		if got != p.expected || got.IsKnown() != p.known {
+			t.Errorf("got %q (known %t), expected %q (known %t)", got, got.IsKnown(), p.expected, p.known)
+		}
+	}
+}
+
+func TestListDNSUnknownType(t *testing.T) {
+	api, srv := newTestApi(t)
+	srv.AddRecord("example.com", s90test.Record{Name: "key", TTL: "60", Type: "TYPE65280", IP: `\# 2 abcd`})
+
+	sid, err := api.Login(Credentials{UID: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	domains, err := api.ListDomains(sid)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	records, err := api.ListDNS(SessionDomain{SID: sid, DomainID: domains[0].DomainID})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(records) != 1 || records[0].Type != "TYPE65280" || records[0].Type.IsKnown() || records[0].Data != nil {
+		t.Errorf("got %+v, expected record of unknown type TYPE65280", records)
+	}
+}
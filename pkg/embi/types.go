@@ -1,6 +1,7 @@
 package embi
 
 import (
+	"strings"
 	"time"
 )
 
@@ -14,6 +15,11 @@ type Domain struct {
 	Zone     string
 }
 
+// PriorityUnset is the Priority of a record listed without a priority,
+// distinct from priority 0, which is valid for MX and SRV records.
+// AddDNS sends no priority for it.
+const PriorityUnset = -1
+
 type DNSRecord struct {
 	ID       string
 	Name     string
@@ -22,6 +28,26 @@ type DNSRecord struct {
 	IP       string
 	Priority int
 	Locked   bool
+
+	// Data is the typed value of the record. ListDNS sets it for records of known types
+	// and AddDNS sends it in place of Type, IP and Priority when IP is empty.
+	Data RData
+}
+
+// SetData sets the typed value of the record together with its Type, IP and Priority.
+func (r *DNSRecord) SetData(data RData) {
+	r.Data = data
+	r.Type = data.Type()
+	r.IP, r.Priority = data.wire()
+}
+
+// wire returns the type, value and priority of the record as sent to the API.
+func (r *DNSRecord) wire() (DNSType, string, int) {
+	if r.IP == "" && r.Data != nil {
+		value, priority := r.Data.wire()
+		return r.Data.Type(), value, priority
+	}
+	return r.Type, r.IP, r.Priority
 }
 
 const (
@@ -36,8 +62,16 @@ const (
 	DNSTypeSSHFP DNSType = "SSHFP"
 	DNSTypeTXT   DNSType = "TXT"
 	DNSTypeCAA   DNSType = "CAA"
+	DNSTypePTR   DNSType = "PTR"
+	DNSTypeTLSA  DNSType = "TLSA"
+	DNSTypeSVCB  DNSType = "SVCB"
+	DNSTypeHTTPS DNSType = "HTTPS"
+	DNSTypeNAPTR DNSType = "NAPTR"
+	DNSTypeDS    DNSType = "DS"
 )
 
+// DNSType is the type of a DNS record. Types unknown to this package are kept
+// as returned by the API, so records of them can be listed and added again.
 type DNSType string
 
 func (DNSType) sealed() {}
@@ -46,33 +80,30 @@ func (t DNSType) String() string {
 	return string(t)
 }
 
-func DNSTypeFromString(s string) DNSType {
-	switch s {
-	case "A":
-		return DNSTypeA
-	case "AAAA":
-		return DNSTypeAAAA
-	case "CNAME":
-		return DNSTypeCNAME
-	case "DNAME":
-		return DNSTypeDNAME
-	case "LOC":
-		return DNSTypeLOC
-	case "MX":
-		return DNSTypeMX
-	case "NS":
-		return DNSTypeNS
-	case "SRV":
-		return DNSTypeSRV
-	case "SSHFP":
-		return DNSTypeSSHFP
-	case "TXT":
-		return DNSTypeTXT
-	case "CAA":
-		return DNSTypeCAA
+// IsKnown reports whether the type is one of the DNSType constants.
+func (t DNSType) IsKnown() bool {
+	switch t {
+	case DNSTypeA, DNSTypeAAAA, DNSTypeCNAME, DNSTypeDNAME, DNSTypeLOC, DNSTypeMX, DNSTypeNS,
+		DNSTypeSRV, DNSTypeSSHFP, DNSTypeTXT, DNSTypeCAA, DNSTypePTR, DNSTypeTLSA, DNSTypeSVCB,
+		DNSTypeHTTPS, DNSTypeNAPTR, DNSTypeDS:
+		return true
 	default:
-		return ""
+		return false
+	}
+}
+
+// HasPriority reports whether records of the type have a priority, i.e. MX and SRV records.
+func (t DNSType) HasPriority() bool {
+	return t == DNSTypeMX || t == DNSTypeSRV
+}
+
+// DNSTypeFromString returns the DNSType of the name. Known types are matched
+// case-insensitively, unknown types are returned unchanged.
+func DNSTypeFromString(s string) DNSType {
+	if t := DNSType(strings.ToUpper(s)); t.IsKnown() {
+		return t
 	}
+	return DNSType(s)
 }
 
 type SessionDomain struct {
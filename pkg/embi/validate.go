@@ -0,0 +1,222 @@
+package embi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	MinTTL = time.Second                 // MinTTL is the lowest TTL accepted by Validate.
+	MaxTTL = math.MaxInt32 * time.Second // MaxTTL is the highest TTL allowed by RFC 2181.
+)
+
+const (
+	maxLabelLen = 63
+	maxNameLen  = 253
+)
+
+// ErrInvalidRecord matches a ValidationError.
+var ErrInvalidRecord = errors.New("invalid DNS record")
+
+// FieldError describes an invalid field of a DNS record.
+type FieldError struct {
+	Field  string // Field is the name of the DNSRecord field, e.g. "IP".
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// ValidationError is returned by DNSRecord.Validate with all invalid fields of the record.
+type ValidationError struct {
+	Name   string
+	Type   DNSType
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("systems90: %s %s %q: %s", ErrInvalidRecord, e.Type, e.Name, strings.Join(msgs, "; "))
+}
+
+// Is reports whether the target is ErrInvalidRecord.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidRecord
+}
+
+// Validate checks the record before it is sent to the API: the name relative to the zone,
+// the TTL bounds, the value syntax of known types and the priority of MX and SRV records.
+// A record given only by Data is checked by its wire value.
+func (r *DNSRecord) Validate() error {
+	return r.validate(nil)
+}
+
+// ValidateIn is like Validate but also checks that the record does not conflict
+// with the existing records of the zone, e.g. a CNAME sharing its name with other records.
+func (r *DNSRecord) ValidateIn(existing []DNSRecord) error {
+	return r.validate(existing)
+}
+
+func (r *DNSRecord) validate(existing []DNSRecord) error {
+	typ, value, priority := r.wire()
+	v := &ValidationError{Name: r.Name, Type: typ}
+
+	if err := checkName(r.Name, true); err != nil {
+		v.add("Name", err.Error())
+	}
+	if typ == DNSTypeCNAME && r.Name == "" {
+		v.add("Name", "CNAME is not allowed at the zone apex")
+	}
+
+	switch {
+	case r.TTL < MinTTL:
+		v.add("TTL", fmt.Sprintf("%s is below %s", r.TTL, MinTTL))
+	case r.TTL > MaxTTL:
+		v.add("TTL", fmt.Sprintf("%s is above %s", r.TTL, MaxTTL))
+	case r.TTL%time.Second != 0:
+		v.add("TTL", fmt.Sprintf("%s is not whole seconds", r.TTL))
+	}
+
+	// Values of unknown types are passed to the API unchecked.
+	switch {
+	case typ == "":
+		v.add("Type", "missing")
+	case typ.IsKnown():
+		if err := checkValue(typ, value); err != nil {
+			v.add("IP", err.Error())
+		}
+	}
+
+	switch {
+	case typ.HasPriority() && priority == PriorityUnset:
+		v.add("Priority", fmt.Sprintf("missing for %s records", typ))
+	case typ.HasPriority() && (priority < 0 || priority > math.MaxUint16):
+		v.add("Priority", fmt.Sprintf("%d out of range 0-65535", priority))
+	case !typ.HasPriority() && typ.IsKnown() && priority != 0 && priority != PriorityUnset:
+		v.add("Priority", fmt.Sprintf("not allowed for %s records", typ))
+	}
+
+	for _, rec := range existing {
+		if !strings.EqualFold(rec.Name, r.Name) || rec.ID == r.ID && r.ID != "" {
+			continue
+		}
+		if typ == DNSTypeCNAME || rec.Type == DNSTypeCNAME {
+			v.add("Name", fmt.Sprintf("conflicts with %s record %s, a CNAME must be the only record of its name", rec.Type, rec.ID))
+			break
+		}
+	}
+
+	if len(v.Fields) != 0 {
+		return v
+	}
+	return nil
+}
+
+func (v *ValidationError) add(field, reason string) {
+	v.Fields = append(v.Fields, &FieldError{Field: field, Reason: reason})
+}
+
+// checkValue parses the value and checks the host names it contains.
+// The priority is checked on its own, so the value is parsed with a valid one.
+func checkValue(typ DNSType, value string) error {
+	data, err := parseRData(typ, value, 0)
+	if err != nil {
+		return err
+	}
+
+	var host string
+	switch d := data.(type) {
+	case RDataCNAME:
+		host = d.Target
+	case RDataDNAME:
+		host = d.Target
+	case RDataNS:
+		host = d.Host
+	case RDataPTR:
+		host = d.Target
+	case RDataMX:
+		host = d.Host
+	case RDataSRV:
+		host = d.Target
+	case RDataSVCB:
+		host = d.Target
+	case RDataHTTPS:
+		host = d.Target
+	case RDataNAPTR:
+		host = d.Replacement
+	case RDataCAA:
+		if d.Flags != 0 && d.Flags != 128 {
+			return fmt.Errorf("CAA flags %d, expected 0 or 128", d.Flags)
+		}
+		if !isAlnum(d.Tag) {
+			return fmt.Errorf("CAA tag %q is not alphanumeric", d.Tag)
+		}
+		return nil
+	case RDataTXT:
+		if len(d.Text) > math.MaxUint16 {
+			return errors.New("TXT value too long")
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	// The root name means no target for SRV, SVCB and NAPTR records.
+	if host == "." {
+		return nil
+	}
+	return checkName(strings.TrimSuffix(host, "."), false)
+}
+
+// checkName checks the length and characters of the name labels.
+// Owner names may be empty for the apex and start with a wildcard label.
+func checkName(name string, owner bool) error {
+	if name == "" {
+		if owner {
+			return nil
+		}
+		return errors.New("empty host name")
+	}
+	if len(name) > maxNameLen {
+		return fmt.Errorf("name longer than %d characters", maxNameLen)
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		switch {
+		case label == "":
+			return fmt.Errorf("empty label in %q", name)
+		case len(label) > maxLabelLen:
+			return fmt.Errorf("label %q longer than %d characters", label, maxLabelLen)
+		case label == "*" && owner && i == 0:
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return fmt.Errorf("label %q starts or ends with a hyphen", label)
+		default:
+			for _, c := range label {
+				if !isAlnum(string(c)) && c != '-' && c != '_' {
+					return fmt.Errorf("invalid character %q in label %q", c, label)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isAlnum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
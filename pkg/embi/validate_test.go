@@ -0,0 +1,81 @@
+package embi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	params := []struct {
+		name   string
+		record DNSRecord
+		fields []string
+	}{
+		{name: "valid A", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1"}},
+		{name: "valid wildcard", record: DNSRecord{Name: "*.dev", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1"}},
+		{name: "valid challenge", record: DNSRecord{Name: "_acme-challenge", TTL: time.Minute, Type: DNSTypeTXT, IP: "token"}},
+		{name: "valid MX", record: DNSRecord{TTL: time.Hour, Type: DNSTypeMX, IP: "mx.example.com.", Priority: 10}},
+		{name: "valid data", record: DNSRecord{Name: "www", TTL: time.Hour, Data: RDataCNAME{Target: "example.com."}}},
+		{name: "A with IPv6", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "2001:db8::1"}, fields: []string{"IP"}},
+		{name: "CNAME at apex", record: DNSRecord{TTL: time.Hour, Type: DNSTypeCNAME, IP: "example.net."}, fields: []string{"Name"}},
+		{name: "zero TTL", record: DNSRecord{Name: "www", Type: DNSTypeA, IP: "192.0.2.1"}, fields: []string{"TTL"}},
+		{name: "malformed CAA", record: DNSRecord{TTL: time.Hour, Type: DNSTypeCAA, IP: `7 issue "ca.example"`}, fields: []string{"IP"}},
+		{name: "long label", record: DNSRecord{Name: string(make([]byte, 64)), TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1"}, fields: []string{"Name"}},
+		{name: "invalid charset", record: DNSRecord{Name: "a b", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1"}, fields: []string{"Name"}},
+		{name: "invalid target", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeCNAME, IP: "-bad-.example."}, fields: []string{"IP"}},
+		{name: "MX priority", record: DNSRecord{TTL: time.Hour, Type: DNSTypeMX, IP: "mx.example.com.", Priority: 70000}, fields: []string{"Priority"}},
+		{name: "priority of A", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1", Priority: 5}, fields: []string{"Priority"}},
+		{name: "unset MX priority", record: DNSRecord{TTL: time.Hour, Type: DNSTypeMX, IP: "mx.example.com.", Priority: PriorityUnset}, fields: []string{"Priority"}},
+		{name: "unset A priority", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1", Priority: PriorityUnset}},
+		{name: "missing type", record: DNSRecord{Name: "www", TTL: time.Hour, IP: "192.0.2.1"}, fields: []string{"Type"}},
+		{name: "several fields", record: DNSRecord{Name: "a..b", Type: DNSTypeA, IP: "x"}, fields: []string{"Name", "TTL", "IP"}},
+	}
+
+	for _, p := range params {
+		t.Run(p.name, func(t *testing.T) {
+			err := p.record.Validate()
+
+			var fields []string
+			var v *ValidationError
+			if errors.As(err, &v) {
+				for _, f := range v.Fields {
+					fields = append(fields, f.Field)
+				}
+			}
+			if !slices.Equal(fields, p.fields) {
+				t.Errorf("got %v (%v), expected %v", fields, err, p.fields)
+			}
+			if p.fields != nil && !errors.Is(err, ErrInvalidRecord) {
+				t.Errorf("got %v, expected %s", err, ErrInvalidRecord)
+			}
+		})
+	}
+}
+
+func TestValidateIn(t *testing.T) {
+	existing := []DNSRecord{
+		{ID: "1", Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.1"},
+		{ID: "2", Name: "alias", TTL: time.Hour, Type: DNSTypeCNAME, IP: "www.example.com."},
+	}
+
+	params := []struct {
+		name   string
+		record DNSRecord
+		valid  bool
+	}{
+		{name: "CNAME on free name", record: DNSRecord{Name: "other", TTL: time.Hour, Type: DNSTypeCNAME, IP: "www.example.com."}, valid: true},
+		{name: "CNAME on used name in other case", record: DNSRecord{Name: "WWW", TTL: time.Hour, Type: DNSTypeCNAME, IP: "example.com."}},
+		{name: "CNAME on used name", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeCNAME, IP: "example.com."}},
+		{name: "record on CNAME name", record: DNSRecord{Name: "alias", TTL: time.Hour, Type: DNSTypeTXT, IP: "text"}},
+		{name: "replacing the CNAME", record: DNSRecord{ID: "2", Name: "alias", TTL: time.Hour, Type: DNSTypeCNAME, IP: "example.com."}, valid: true},
+		{name: "second A record", record: DNSRecord{Name: "www", TTL: time.Hour, Type: DNSTypeA, IP: "192.0.2.2"}, valid: true},
+	}
+
+	for _, p := range params {
+		if err := p.record.ValidateIn(existing); (err == nil) != p.valid {
+			t.Errorf("%s: got %v, expected valid %t", p.name, err, p.valid)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package s90dyndns implements the dyndns2 update protocol (/nic/update) on top of the Systems90 API,
+// so routers can update A and AAAA records of Systems90 zones.
+//
+// Clients authenticate with HTTP basic auth using their Systems90 credentials.
+package s90dyndns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// Return codes of the dyndns2 protocol.
+const (
+	codeGood     = "good"
+	codeNoChange = "nochg"
+	codeNoHost   = "nohost"
+	codeBadAuth  = "badauth"
+	codeNotFQDN  = "notfqdn"
+	codeDNSErr   = "dnserr"
+	codeServer   = "911"
+)
+
+// Handler serves dyndns2 update requests, usually mounted at /nic/update.
+// The zero value is ready to use.
+type Handler struct {
+	// Options are passed to client.NewClient.
+	Options []client.Option
+	// TTL of updated records, client.DefaultTTL when zero.
+	TTL time.Duration
+	// TrustForwardedFor makes the handler take the address of the client from
+	// the X-Forwarded-For header when the request has no myip parameter.
+	TrustForwardedFor bool
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	uid, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="dyndns"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintln(w, codeBadAuth)
+		return
+	}
+
+	hostnames := splitList(r.URL.Query().Get("hostname"))
+	if len(hostnames) == 0 {
+		fmt.Fprintln(w, codeNotFQDN)
+		return
+	}
+
+	addrs, err := h.addresses(r)
+	if err != nil {
+		fmt.Fprintln(w, codeServer)
+		return
+	}
+
+	ctx := r.Context()
+	c, err := client.NewClientContext(ctx, client.Credentials{UID: uid, Password: password}, h.Options...)
+	switch {
+	case errors.Is(err, client.ErrForbidden):
+		fmt.Fprintln(w, codeBadAuth)
+		return
+	case err != nil:
+		log.Printf("s90dyndns: login: %s", err)
+		fmt.Fprintln(w, codeServer)
+		return
+	}
+	defer c.CloseContext(context.WithoutCancel(ctx))
+
+	domains, err := c.ListDomainsContext(ctx)
+	if err != nil {
+		log.Printf("s90dyndns: listing domains: %s", err)
+		fmt.Fprintln(w, codeServer)
+		return
+	}
+
+	for _, hostname := range hostnames {
+		fmt.Fprintln(w, h.update(ctx, c, domains, hostname, addrs))
+	}
+}
+
+// update sets the addresses of the hostname and returns the response line.
+func (h *Handler) update(ctx context.Context, c *client.Client, domains []client.Domain, hostname string, addrs []netip.Addr) string {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	if !strings.Contains(hostname, ".") {
+		return codeNotFQDN
+	}
+
+	domain, name, ok := findZone(domains, hostname)
+	if !ok {
+		return codeNoHost
+	}
+	dc := c.DomainFor(domain)
+
+	ttl := h.TTL
+	if ttl <= 0 {
+		ttl = client.DefaultTTL
+	}
+
+	desired := make([]client.DNSRecord, len(addrs))
+	for i, addr := range addrs {
+		desired[i] = client.DNSRecord{Name: name, TTL: ttl, Type: client.DNSTypeA, IP: addr.String()}
+		if addr.Is6() {
+			desired[i].Type = client.DNSTypeAAAA
+		}
+	}
+
+	plan, err := dc.ApplyContext(ctx, desired)
+	if err != nil {
+		log.Printf("s90dyndns: updating %s: %s", hostname, err)
+		return codeDNSErr
+	}
+
+	code := codeGood
+	if plan.Empty() {
+		code = codeNoChange
+	}
+	return code + " " + joinAddrs(addrs)
+}
+
+// addresses returns the addresses given by the myip parameter, or the address of the client.
+func (h *Handler) addresses(r *http.Request) ([]netip.Addr, error) {
+	values := splitList(r.URL.Query().Get("myip"))
+	if len(values) == 0 {
+		values = []string{h.remoteAddr(r)}
+	}
+
+	var addrs []netip.Addr
+	seen := map[bool]bool{} // at most one address per family
+	for _, v := range values {
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		if seen[addr.Is6()] {
+			return nil, fmt.Errorf("more addresses of the same family given")
+		}
+		seen[addr.Is6()] = true
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func (h *Handler) remoteAddr(r *http.Request) string {
+	if h.TrustForwardedFor {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// findZone returns the domain of the most specific zone containing the hostname
+// and the name relative to it.
+func findZone(domains []client.Domain, hostname string) (domain client.Domain, name string, ok bool) {
+	var suffix string
+	for _, d := range domains {
+		z := strings.ToLower(strings.TrimSuffix(d.Zone, "."))
+		switch {
+		case hostname == z:
+			return d, "", true
+		case strings.HasSuffix(hostname, "."+z) && len(z)+1 > len(suffix):
+			domain, suffix = d, "."+z
+		}
+	}
+	if suffix == "" {
+		return client.Domain{}, "", false
+	}
+	return domain, strings.TrimSuffix(hostname, suffix), true
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func joinAddrs(addrs []netip.Addr) string {
+	parts := make([]string, len(addrs))
+	for i, addr := range addrs {
+		parts[i] = addr.String()
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,58 @@
+package s90dyndns
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestUpdate(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddRecord("example.com", s90test.Record{Name: "home", TTL: "30", Type: "A", IP: "192.0.2.1"})
+
+	handler := &Handler{Options: []client.Option{client.WithBaseURL(srv.URL)}}
+
+	params := []struct {
+		name     string
+		query    string
+		user     string
+		expected string
+	}{
+		{name: "changed address", query: "hostname=home.example.com&myip=198.51.100.1", user: "user", expected: "good 198.51.100.1\n"},
+		{name: "same address", query: "hostname=home.example.com&myip=198.51.100.1", user: "user", expected: "nochg 198.51.100.1\n"},
+		{name: "address of client", query: "hostname=example.com", user: "user", expected: "good 203.0.113.1\n"},
+		{name: "dual stack", query: "hostname=home.example.com&myip=198.51.100.1,2001:db8::1", user: "user", expected: "good 198.51.100.1,2001:db8::1\n"},
+		{name: "unmanaged host", query: "hostname=home.example.org&myip=198.51.100.1", user: "user", expected: "nohost\n"},
+		{name: "wrong password", query: "hostname=home.example.com&myip=198.51.100.1", user: "other", expected: "badauth\n"},
+	}
+
+	for _, param := range params {
+		t.Run(param.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/nic/update?"+param.query, nil)
+			req.RemoteAddr = "203.0.113.1:4321"
+			req.SetBasicAuth(param.user, "secret")
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != param.expected {
+				t.Errorf("got %q, expected %q", got, param.expected)
+			}
+		})
+	}
+
+	records := srv.Records("example.com")
+	if len(records) != 3 {
+		t.Errorf("got %+v, expected A and AAAA of home and A of apex", records)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected 0", got)
+	}
+}
@@ -0,0 +1,81 @@
+package s90externaldns
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// NewHandler returns the HTTP handler of the webhook serving
+// the negotiate, records, adjustendpoints and apply-changes endpoints.
+func NewHandler(p *Provider) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, p.DomainFilter())
+	})
+
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			endpoints, err := p.Records(r.Context())
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if endpoints == nil {
+				endpoints = []*Endpoint{}
+			}
+			writeJSON(w, http.StatusOK, endpoints)
+
+		case http.MethodPost:
+			var changes Changes
+			if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := p.ApplyChanges(r.Context(), &changes); err != nil {
+				writeError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/adjustendpoints", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var endpoints []*Endpoint
+		if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, p.AdjustEndpoints(endpoints))
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", MediaType)
+	w.Header().Set("Vary", "Content-Type")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("s90externaldns: writing response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	log.Printf("s90externaldns: %s", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,122 @@
+package s90externaldns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func newTestHandler(t *testing.T, filter DomainFilter) (http.Handler, *s90test.Server) {
+	t.Helper()
+
+	srv := s90test.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddDomain("user", "sub.example.com")
+	srv.AddDomain("user", "example.org")
+
+	c, err := client.NewClient(client.Credentials{UID: "user", Password: "secret"}, client.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return NewHandler(NewProvider(c, filter)), srv
+}
+
+func TestRecords(t *testing.T) {
+	handler, srv := newTestHandler(t, DomainFilter{Include: []string{"example.com"}})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.2"})
+	srv.AddRecord("sub.example.com", s90test.Record{Name: "", TTL: "300", Type: "MX", IP: "mx.example.com", Priority: "10"})
+	srv.AddRecord("example.org", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.3"})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/records", nil))
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != MediaType {
+		t.Fatalf("got %d %s, expected 200 with webhook media type", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	var endpoints []Endpoint
+	if err := json.NewDecoder(w.Body).Decode(&endpoints); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %+v, expected 2 endpoints", endpoints)
+	}
+	if ep := endpoints[1]; ep.DNSName != "www.example.com" || len(ep.Targets) != 2 || ep.RecordTTL != 60 {
+		t.Errorf("got %+v, expected A endpoint with 2 targets", ep)
+	}
+	if ep := endpoints[0]; ep.DNSName != "sub.example.com" || ep.RecordType != "MX" || ep.Targets[0] != "10 mx.example.com" {
+		t.Errorf("got %+v, expected MX endpoint of sub.example.com", ep)
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	handler, srv := newTestHandler(t, DomainFilter{})
+	srv.AddRecord("example.com", s90test.Record{Name: "old", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	srv.AddRecord("example.com", s90test.Record{Name: "app", TTL: "60", Type: "A", IP: "192.0.2.2"})
+
+	body := `{
+		"Create": [
+			{"dnsName": "new.sub.example.com", "targets": ["192.0.2.3"], "recordType": "A", "recordTTL": 120},
+			{"dnsName": "a-new.sub.example.com", "targets": ["\"heritage=external-dns,external-dns/owner=default\""], "recordType": "TXT"}
+		],
+		"UpdateOld": [{"dnsName": "app.example.com", "targets": ["192.0.2.2"], "recordType": "A", "recordTTL": 60}],
+		"UpdateNew": [{"dnsName": "app.example.com", "targets": ["192.0.2.4"], "recordType": "A", "recordTTL": 60}],
+		"Delete": [{"dnsName": "old.example.com", "targets": ["192.0.2.1"], "recordType": "A"}]
+	}`
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/records", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got %d %s, expected 204", w.Code, w.Body)
+	}
+
+	if records := srv.Records("example.com"); len(records) != 1 || records[0].Name != "app" || records[0].IP != "192.0.2.4" {
+		t.Errorf("got %+v, expected only updated app record", records)
+	}
+
+	records := srv.Records("sub.example.com")
+	if len(records) != 2 || records[0].Name != "new" || records[0].TTL != "120" || records[1].IP != `"heritage=external-dns,external-dns/owner=default"` {
+		t.Errorf("got %+v, expected created A and TXT registry records", records)
+	}
+}
+
+func TestUnsetPriority(t *testing.T) {
+	handler, srv := newTestHandler(t, DomainFilter{Include: []string{"example.org"}})
+	srv.AddRecord("example.org", s90test.Record{Name: "", TTL: "300", Type: "MX", IP: "mx.example.org"})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/records", nil))
+
+	var endpoints []Endpoint
+	if err := json.NewDecoder(w.Body).Decode(&endpoints); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(endpoints) != 1 || len(endpoints[0].Targets) != 1 || endpoints[0].Targets[0] != "0 mx.example.org" {
+		t.Fatalf("got %+v, expected MX target with priority 0", endpoints)
+	}
+
+	body, err := json.Marshal(Changes{Delete: []*Endpoint{&endpoints[0]}})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/records", strings.NewReader(string(body))))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got %d %s, expected 204", w.Code, w.Body)
+	}
+	if records := srv.Records("example.org"); len(records) != 0 {
+		t.Errorf("got %+v, expected the MX record deleted", records)
+	}
+}
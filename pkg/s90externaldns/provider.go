@@ -0,0 +1,242 @@
+// Package s90externaldns implements an external-dns webhook provider for the Systems90 API.
+//
+// TXT records are stored verbatim, so external-dns can keep its TXT ownership
+// registry records next to the records it manages.
+package s90externaldns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// Provider translates external-dns endpoints into DNS records of all zones
+// managed by the client and matched by the domain filter.
+type Provider struct {
+	client *client.Client
+	filter DomainFilter
+}
+
+// NewProvider creates a provider using the logged in client.
+func NewProvider(c *client.Client, filter DomainFilter) *Provider {
+	return &Provider{
+		client: c,
+		filter: filter,
+	}
+}
+
+// DomainFilter returns the filter of managed zones.
+func (p *Provider) DomainFilter() DomainFilter {
+	return p.filter
+}
+
+// Records returns all records of the managed zones, one endpoint per name and type,
+// sorted by name and type.
+func (p *Provider) Records(ctx context.Context) ([]*Endpoint, error) {
+	zones, err := p.zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*Endpoint
+	for _, z := range zones {
+		records, err := z.dc.ListDNSRecordsContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		index := make(map[[2]string]*Endpoint)
+		for _, rec := range records {
+			name := absoluteName(rec.Name, z.name)
+			key := [2]string{name, rec.Type.String()}
+
+			ep, ok := index[key]
+			if !ok {
+				ep = &Endpoint{
+					DNSName:    name,
+					RecordType: rec.Type.String(),
+					RecordTTL:  int64(rec.TTL / time.Second),
+				}
+				index[key] = ep
+				endpoints = append(endpoints, ep)
+			}
+			ep.Targets = append(ep.Targets, target(rec))
+		}
+	}
+
+	slices.SortStableFunc(endpoints, func(a, b *Endpoint) int {
+		if c := strings.Compare(a.DNSName, b.DNSName); c != 0 {
+			return c
+		}
+		return strings.Compare(a.RecordType, b.RecordType)
+	})
+	return endpoints, nil
+}
+
+// AdjustEndpoints sets the default TTL on endpoints without one,
+// so that they compare equal to the records returned by Records.
+func (p *Provider) AdjustEndpoints(endpoints []*Endpoint) []*Endpoint {
+	for _, ep := range endpoints {
+		if ep.RecordTTL <= 0 {
+			ep.RecordTTL = int64(client.DefaultTTL / time.Second)
+		}
+	}
+	return endpoints
+}
+
+// ApplyChanges creates, updates and deletes records of the endpoints.
+// Updated endpoints replace all records of their name and type.
+func (p *Provider) ApplyChanges(ctx context.Context, changes *Changes) error {
+	zones, err := p.zones(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ep := range changes.Delete {
+		errs = append(errs, p.deleteEndpoint(ctx, zones, ep))
+	}
+
+	for _, ep := range changes.UpdateNew {
+		z, records, err := endpointRecords(zones, ep)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := z.dc.ApplyContext(ctx, records); err != nil {
+			errs = append(errs, fmt.Errorf("update %s %s: %w", ep.RecordType, ep.DNSName, err))
+		}
+	}
+
+	for _, ep := range changes.Create {
+		z, records, err := endpointRecords(zones, ep)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, rec := range records {
+			_, err := z.dc.AddDNSRecordContext(ctx, rec.Name, rec.IP, rec.Type,
+				client.DNSRecordTTL(rec.TTL), client.DNSRecordPriority(rec.Priority))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("create %s %s: %w", ep.RecordType, ep.DNSName, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Provider) deleteEndpoint(ctx context.Context, zones []zone, ep *Endpoint) error {
+	z, records, err := endpointRecords(zones, ep)
+	if err != nil {
+		return err
+	}
+
+	existing, err := z.dc.ListDNSRecordsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, have := range existing {
+		if have.Locked || !slices.ContainsFunc(records, func(want client.DNSRecord) bool {
+			return strings.EqualFold(have.Name, want.Name) && have.Type == want.Type &&
+				have.IP == want.IP && (!have.Type.HasPriority() || max(have.Priority, 0) == want.Priority)
+		}) {
+			continue
+		}
+
+		if err := z.dc.RemoveDNSRecordByIDContext(ctx, have.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s %s: %w", ep.RecordType, ep.DNSName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type zone struct {
+	name string
+	dc   *client.DomainClient
+}
+
+// zones returns the managed zones matched by the domain filter, longest names first.
+func (p *Provider) zones(ctx context.Context) ([]zone, error) {
+	domains, err := p.client.ListDomainsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []zone
+	for _, d := range domains {
+		if !p.filter.Match(d.Zone) {
+			continue
+		}
+
+		zones = append(zones, zone{name: normalizeName(d.Zone), dc: p.client.DomainFor(d)})
+	}
+
+	slices.SortFunc(zones, func(a, b zone) int { return len(b.name) - len(a.name) })
+	return zones, nil
+}
+
+// endpointRecords returns the zone of the endpoint and its targets as DNS records.
+func endpointRecords(zones []zone, ep *Endpoint) (zone, []client.DNSRecord, error) {
+	name := normalizeName(ep.DNSName)
+
+	i := slices.IndexFunc(zones, func(z zone) bool {
+		return name == z.name || strings.HasSuffix(name, "."+z.name)
+	})
+	if i < 0 {
+		return zone{}, nil, fmt.Errorf("%s: %w", ep.DNSName, client.ErrDomainNotManaged)
+	}
+	z := zones[i]
+
+	ttl := time.Duration(ep.RecordTTL) * time.Second
+	if ttl <= 0 {
+		ttl = client.DefaultTTL
+	}
+
+	records := make([]client.DNSRecord, 0, len(ep.Targets))
+	for _, t := range ep.Targets {
+		rec := client.DNSRecord{
+			Name: strings.TrimSuffix(strings.TrimSuffix(name, z.name), "."),
+			TTL:  ttl,
+			Type: client.DNSType(ep.RecordType),
+			IP:   t,
+		}
+
+		switch rec.Type {
+		case client.DNSTypeMX, client.DNSTypeSRV:
+			priority, value, _ := strings.Cut(t, " ")
+			p, err := strconv.ParseUint(priority, 10, 16)
+			if err != nil {
+				return zone{}, nil, fmt.Errorf("%s: invalid %s target %q", ep.DNSName, rec.Type, t)
+			}
+			rec.Priority, rec.IP = int(p), value
+		}
+		records = append(records, rec)
+	}
+	return z, records, nil
+}
+
+func absoluteName(name, zone string) string {
+	if name == "" || name == "@" {
+		return zone
+	}
+	return strings.ToLower(name) + "." + zone
+}
+
+func target(rec client.DNSRecord) string {
+	switch rec.Type {
+	case client.DNSTypeMX, client.DNSTypeSRV:
+		// Records listed without a priority get 0, as a target cannot leave it out.
+		return strconv.Itoa(max(rec.Priority, 0)) + " " + rec.IP
+	default:
+		return rec.IP
+	}
+}
@@ -0,0 +1,71 @@
+package s90externaldns
+
+import (
+	"strings"
+)
+
+// MediaType is the media type of the external-dns webhook protocol.
+const MediaType = "application/external.dns.webhook+json;version=1"
+
+// Endpoint is a DNS name with its targets as exchanged with external-dns.
+type Endpoint struct {
+	DNSName          string                     `json:"dnsName,omitempty"`
+	Targets          []string                   `json:"targets,omitempty"`
+	RecordType       string                     `json:"recordType,omitempty"`
+	SetIdentifier    string                     `json:"setIdentifier,omitempty"`
+	RecordTTL        int64                      `json:"recordTTL,omitempty"`
+	Labels           map[string]string          `json:"labels,omitempty"`
+	ProviderSpecific []ProviderSpecificProperty `json:"providerSpecific,omitempty"`
+}
+
+// ProviderSpecificProperty is a provider specific setting of an Endpoint.
+type ProviderSpecificProperty struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Changes are the endpoints to create, update and delete sent by external-dns.
+type Changes struct {
+	Create    []*Endpoint `json:"Create,omitempty"`
+	UpdateOld []*Endpoint `json:"UpdateOld,omitempty"`
+	UpdateNew []*Endpoint `json:"UpdateNew,omitempty"`
+	Delete    []*Endpoint `json:"Delete,omitempty"`
+}
+
+// DomainFilter restricts the zones managed by the provider.
+// An empty Include matches all zones.
+type DomainFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Match reports whether the domain is matched by the filter.
+func (f DomainFilter) Match(domain string) bool {
+	domain = normalizeName(domain)
+	for _, ex := range f.Exclude {
+		if matchDomain(domain, ex) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, in := range f.Include {
+		if matchDomain(domain, in) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDomain(domain, filter string) bool {
+	filter = normalizeName(filter)
+	if strings.HasPrefix(filter, ".") {
+		return strings.HasSuffix(domain, filter)
+	}
+	return domain == filter || strings.HasSuffix(domain, "."+filter)
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
@@ -0,0 +1,204 @@
+// Package s90lego implements a DNS-01 challenge provider for lego (go-acme).
+//
+// DNSProvider satisfies the challenge.Provider and challenge.ProviderTimeout
+// interfaces of github.com/go-acme/lego/v4/challenge.
+package s90lego
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// Environment variables read by NewDefaultConfig and NewDNSProvider.
+const (
+	EnvUID                = "SYSTEMS90_UID"
+	EnvPassword           = "SYSTEMS90_PASSWORD"
+	EnvTTL                = "SYSTEMS90_TTL"
+	EnvPropagationTimeout = "SYSTEMS90_PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = "SYSTEMS90_POLLING_INTERVAL"
+)
+
+// Config is the configuration of DNSProvider.
+type Config struct {
+	UID      string
+	Password string
+
+	TTL                time.Duration
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+
+	// Options are passed to client.NewClient.
+	Options []client.Option
+}
+
+// NewDefaultConfig returns a configuration with values from the environment,
+// TTL, propagation timeout and polling interval given in seconds.
+func NewDefaultConfig() *Config {
+	return &Config{
+		UID:                os.Getenv(EnvUID),
+		Password:           os.Getenv(EnvPassword),
+		TTL:                envSeconds(EnvTTL, 2*time.Minute),
+		PropagationTimeout: envSeconds(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    envSeconds(EnvPollingInterval, 5*time.Second),
+	}
+}
+
+func envSeconds(key string, fallback time.Duration) time.Duration {
+	secs, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || secs <= 0 {
+		return fallback
+	}
+	return time.Duration(secs) * time.Second
+}
+
+type challengeRecord struct {
+	domain *client.DomainClient
+	id     string
+}
+
+// DNSProvider places _acme-challenge TXT records in Systems90 zones.
+type DNSProvider struct {
+	config *Config
+
+	mu      sync.Mutex
+	client  *client.Client
+	records map[string]challengeRecord // token -> created record
+}
+
+// NewDNSProvider returns a provider configured from the environment.
+func NewDNSProvider() (*DNSProvider, error) {
+	return NewDNSProviderConfig(NewDefaultConfig())
+}
+
+// NewDNSProviderConfig returns a provider with the given configuration.
+// The provider logs in on the first challenge.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("s90lego: the configuration of the DNS provider is nil")
+	}
+	if config.UID == "" || config.Password == "" {
+		return nil, fmt.Errorf("s90lego: missing credentials, set %s and %s", EnvUID, EnvPassword)
+	}
+
+	return &DNSProvider{
+		config:  config,
+		records: make(map[string]challengeRecord),
+	}, nil
+}
+
+// Present creates the TXT record fulfilling the challenge of the domain.
+// Presenting the same token again keeps the record created before.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.records[token]; ok {
+		return nil
+	}
+
+	fqdn, value := challengeRecordFor(domain, keyAuth)
+
+	dc, name, err := d.findZone(fqdn)
+	if err != nil {
+		return err
+	}
+
+	id, err := dc.AddDNSRecord(name, value, client.DNSTypeTXT, client.DNSRecordTTL(d.config.TTL))
+	if err != nil {
+		return fmt.Errorf("s90lego: %w", err)
+	}
+
+	d.records[token] = challengeRecord{domain: dc, id: id}
+	return nil
+}
+
+// CleanUp deletes the TXT record created by Present for the token.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rec, ok := d.records[token]
+	if !ok {
+		return fmt.Errorf("s90lego: unknown record for %s", domain)
+	}
+
+	if err := rec.domain.RemoveDNSRecordByID(rec.id); err != nil {
+		return fmt.Errorf("s90lego: %w", err)
+	}
+
+	delete(d.records, token)
+	return nil
+}
+
+// Close logs out the session of the provider. A later challenge logs in again.
+func (d *DNSProvider) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client == nil {
+		return nil
+	}
+	err := d.client.Close()
+	d.client = nil
+	if err != nil {
+		return fmt.Errorf("s90lego: %w", err)
+	}
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// findZone returns the client of the most specific managed zone containing
+// the fully qualified name and the name relative to that zone.
+func (d *DNSProvider) findZone(fqdn string) (*client.DomainClient, string, error) {
+	if d.client == nil {
+		c, err := client.NewClient(client.Credentials{UID: d.config.UID, Password: d.config.Password}, d.config.Options...)
+		if err != nil {
+			return nil, "", fmt.Errorf("s90lego: %w", err)
+		}
+		d.client = c
+	}
+
+	domains, err := d.client.ListDomains()
+	if err != nil {
+		return nil, "", fmt.Errorf("s90lego: %w", err)
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(fqdn, "."))
+	var zone client.Domain
+	var suffix string
+	for _, dom := range domains {
+		s := "." + strings.ToLower(strings.TrimSuffix(dom.Zone, "."))
+		if strings.HasSuffix(name, s) && len(s) > len(suffix) {
+			zone, suffix = dom, s
+		}
+	}
+	if suffix == "" {
+		return nil, "", fmt.Errorf("s90lego: %w (%s)", client.ErrDomainNotManaged, fqdn)
+	}
+
+	return d.client.DomainFor(zone), strings.TrimSuffix(name, suffix), nil
+}
+
+// challengeRecordFor returns the fully qualified name and value of the challenge TXT record,
+// as computed by lego's dns01.GetRecord.
+func challengeRecordFor(domain, keyAuth string) (fqdn, value string) {
+	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
+	value = base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:])
+
+	domain = strings.TrimPrefix(domain, "*.")
+	fqdn = "_acme-challenge." + strings.TrimSuffix(domain, ".") + "."
+	return fqdn, value
+}
@@ -0,0 +1,68 @@
+package s90lego
+
+import (
+	"testing"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func TestPresentAndCleanUp(t *testing.T) {
+	srv := s90test.NewServer()
+	defer srv.Close()
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+	srv.AddDomain("user", "sub.example.com")
+	otherID := srv.AddRecord("sub.example.com", s90test.Record{Name: "_acme-challenge.deep", TTL: "60", Type: "TXT", IP: "other"})
+
+	config := NewDefaultConfig()
+	config.UID, config.Password = "user", "secret"
+	config.Options = []client.Option{client.WithBaseURL(srv.URL)}
+
+	provider, err := NewDNSProviderConfig(config)
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	// Presenting the token twice must not leave a second record behind.
+	for i := 0; i < 2; i++ {
+		if err := provider.Present("*.deep.sub.example.com", "token", "keyAuth"); err != nil {
+			t.Fatalf("got %s, expected nil", err)
+		}
+	}
+
+	_, value := challengeRecordFor("deep.sub.example.com", "keyAuth")
+	records := srv.Records("sub.example.com")
+	if len(records) != 2 || records[1].Name != "_acme-challenge.deep" || records[1].IP != value || records[1].TTL != "120" {
+		t.Fatalf("got %+v, expected challenge record in sub.example.com", records)
+	}
+	if got := srv.Records("example.com"); len(got) != 0 {
+		t.Errorf("got %+v, expected no records in example.com", got)
+	}
+
+	if err := provider.CleanUp("*.deep.sub.example.com", "token", "keyAuth"); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if records := srv.Records("sub.example.com"); len(records) != 1 || records[0].ID != otherID {
+		t.Errorf("got %+v, expected only the other record", records)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if got := srv.Sessions(); got != 0 {
+		t.Errorf("got %d sessions, expected none after Close", got)
+	}
+}
+
+func TestChallengeRecordFor(t *testing.T) {
+	fqdn, value := challengeRecordFor("example.com", "abc")
+	if fqdn != "_acme-challenge.example.com." {
+		t.Errorf("got %s, expected _acme-challenge.example.com.", fqdn)
+	}
+	// base64url(sha256("abc")) without padding
+	if value != "ungWv48Bz-pBQUDeXa4iI7ADYaOWF3qctBD_YfIAFa0" {
+		t.Errorf("got %s, expected ungWv48Bz-pBQUDeXa4iI7ADYaOWF3qctBD_YfIAFa0", value)
+	}
+}
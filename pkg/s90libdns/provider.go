@@ -0,0 +1,305 @@
+// Package s90libdns implements the libdns interfaces for the Systems90 API,
+// e.g. for DNS-01 challenges in Caddy and certmagic.
+package s90libdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+
+	"barglvojtech.net/systems90api/pkg/client"
+)
+
+// Provider manages DNS records of Systems90 zones.
+// It logs in on first use and stays logged in until Close is called.
+type Provider struct {
+	UID      string `json:"uid,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Options are passed to client.NewClient.
+	Options []client.Option `json:"-"`
+
+	mu      sync.Mutex
+	client  *client.Client
+	domains map[string]*client.DomainClient
+}
+
+var (
+	_ libdns.RecordGetter   = (*Provider)(nil)
+	_ libdns.RecordAppender = (*Provider)(nil)
+	_ libdns.RecordSetter   = (*Provider)(nil)
+	_ libdns.RecordDeleter  = (*Provider)(nil)
+	_ libdns.ZoneLister     = (*Provider)(nil)
+)
+
+// GetRecords returns all records of the zone.
+func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dc, err := p.domain(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := dc.ListDNSRecordsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]libdns.Record, 0, len(records))
+	for _, rec := range records {
+		result = append(result, toLibdns(rec))
+	}
+	return result, nil
+}
+
+// AppendRecords adds the records to the zone and returns them.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dc, err := p.domain(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []libdns.Record
+	for _, r := range recs {
+		rec, err := fromLibdns(r)
+		if err != nil {
+			return added, err
+		}
+
+		rec.ID, err = dc.AddDNSRecordContext(ctx, rec.Name, rec.IP, rec.Type,
+			client.DNSRecordTTL(rec.TTL), client.DNSRecordPriority(rec.Priority))
+		if err != nil {
+			return added, err
+		}
+		added = append(added, toLibdns(rec))
+	}
+	return added, nil
+}
+
+// SetRecords makes the given records the only members of their record sets.
+// Records are added before the replaced ones are deleted; the operation is not atomic.
+func (p *Provider) SetRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dc, err := p.domain(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := make([]client.DNSRecord, 0, len(recs))
+	for _, r := range recs {
+		rec, err := fromLibdns(r)
+		if err != nil {
+			return nil, err
+		}
+		desired = append(desired, rec)
+	}
+
+	if _, err := dc.ApplyContext(ctx, desired); err != nil {
+		return nil, err
+	}
+
+	result := make([]libdns.Record, 0, len(desired))
+	for _, rec := range desired {
+		result = append(result, toLibdns(rec))
+	}
+	return result, nil
+}
+
+// DeleteRecords deletes records of the zone matching the given ones and returns the deleted records.
+// Empty type, zero TTL and empty data of a given record match any value.
+func (p *Provider) DeleteRecords(ctx context.Context, zone string, recs []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dc, err := p.domain(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := dc.ListDNSRecordsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []libdns.Record
+	for _, have := range existing {
+		haveRR := toLibdns(have).RR()
+		if !matchesAny(recs, haveRR) {
+			continue
+		}
+
+		if err := dc.RemoveDNSRecordByIDContext(ctx, have.ID); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, toLibdns(have))
+	}
+	return deleted, nil
+}
+
+// ListZones returns all zones managed by the user.
+func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, err := p.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := c.ListDomainsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]libdns.Zone, len(domains))
+	for i, d := range domains {
+		zones[i] = libdns.Zone{Name: d.Zone + "."}
+	}
+	return zones, nil
+}
+
+// Close logs out when the provider is logged in.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client == nil {
+		return nil
+	}
+
+	err := p.client.Close()
+	p.client, p.domains = nil, nil
+	return err
+}
+
+func (p *Provider) getClient(ctx context.Context) (*client.Client, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	if p.UID == "" || p.Password == "" {
+		return nil, errors.New("s90libdns: missing credentials")
+	}
+
+	c, err := client.NewClientContext(ctx, client.Credentials{UID: p.UID, Password: p.Password}, p.Options...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = c
+	p.domains = make(map[string]*client.DomainClient)
+	return c, nil
+}
+
+// domain returns a client of the zone, given with or without the trailing dot.
+func (p *Provider) domain(ctx context.Context, zone string) (*client.DomainClient, error) {
+	c, err := p.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := normalizeZone(zone)
+	if dc, ok := p.domains[name]; ok {
+		return dc, nil
+	}
+
+	dc, err := c.DomainContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	p.domains[name] = dc
+	return dc, nil
+}
+
+func normalizeZone(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
+
+// matchesAny reports whether any of recs matches rr, treating empty fields of recs as wildcards.
+func matchesAny(recs []libdns.Record, rr libdns.RR) bool {
+	for _, r := range recs {
+		want := r.RR()
+		if want.Name != rr.Name {
+			continue
+		}
+		if want.Type != "" && want.Type != rr.Type {
+			continue
+		}
+		if want.TTL != 0 && want.TTL != rr.TTL {
+			continue
+		}
+		if want.Data != "" && want.Data != rr.Data {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// toLibdns converts the record into the libdns type of its record type.
+func toLibdns(rec client.DNSRecord) libdns.Record {
+	name := rec.Name
+	if name == "" {
+		name = "@"
+	}
+
+	rr := libdns.RR{
+		Name: name,
+		TTL:  rec.TTL,
+		Type: rec.Type.String(),
+		Data: rec.IP,
+	}
+	switch rec.Type {
+	case client.DNSTypeMX, client.DNSTypeSRV:
+		// Records listed without a priority get 0, as libdns cannot leave it out.
+		rr.Data = fmt.Sprintf("%d %s", max(rec.Priority, 0), rec.IP)
+	}
+
+	parsed, err := rr.Parse()
+	if err != nil {
+		return rr
+	}
+	return parsed
+}
+
+// fromLibdns converts the libdns record into a DNS record with the name relative to the zone.
+func fromLibdns(r libdns.Record) (client.DNSRecord, error) {
+	rr := r.RR()
+
+	rec := client.DNSRecord{
+		Name: rr.Name,
+		TTL:  rr.TTL,
+		Type: client.DNSType(strings.ToUpper(rr.Type)),
+		IP:   rr.Data,
+	}
+	if rec.Name == "@" {
+		rec.Name = ""
+	}
+	if rec.TTL < time.Second {
+		rec.TTL = client.DefaultTTL
+	}
+
+	switch rec.Type {
+	case client.DNSTypeMX, client.DNSTypeSRV:
+		priority, data, _ := strings.Cut(rr.Data, " ")
+		p, err := strconv.ParseUint(priority, 10, 16)
+		if err != nil {
+			return rec, fmt.Errorf("s90libdns: invalid %s data %q", rec.Type, rr.Data)
+		}
+		rec.Priority, rec.IP = int(p), data
+	}
+	return rec, nil
+}
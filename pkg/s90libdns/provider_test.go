@@ -0,0 +1,139 @@
+package s90libdns
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+
+	"barglvojtech.net/systems90api/pkg/client"
+	"barglvojtech.net/systems90api/pkg/s90test"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *s90test.Server) {
+	t.Helper()
+
+	srv := s90test.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddUser("user", "secret")
+	srv.AddDomain("user", "example.com")
+
+	p := &Provider{
+		UID:      "user",
+		Password: "secret",
+		Options:  []client.Option{client.WithBaseURL(srv.URL)},
+	}
+	t.Cleanup(func() { p.Close() })
+
+	return p, srv
+}
+
+func TestAppendAndGetRecords(t *testing.T) {
+	p, _ := newTestProvider(t)
+	ctx := context.Background()
+
+	_, err := p.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+		libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mx.example.net."},
+	})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	records, err := p.GetRecords(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	expected := []libdns.RR{
+		{Name: "www", TTL: time.Hour, Type: "A", Data: "192.0.2.1"},
+		{Name: "_acme-challenge", TTL: client.DefaultTTL, Type: "TXT", Data: "token"},
+		{Name: "@", TTL: time.Hour, Type: "MX", Data: "10 mx.example.net."},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("got %d records, expected %d", len(records), len(expected))
+	}
+	for i, rec := range records {
+		if rec.RR() != expected[i] {
+			t.Errorf("got %+v, expected %+v", rec.RR(), expected[i])
+		}
+	}
+	if _, ok := records[2].(libdns.MX); !ok {
+		t.Errorf("got %T, expected libdns.MX", records[2])
+	}
+}
+
+func TestSetAndDeleteRecords(t *testing.T) {
+	p, srv := newTestProvider(t)
+	ctx := context.Background()
+
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.1"})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "A", IP: "192.0.2.2"})
+	srv.AddRecord("example.com", s90test.Record{Name: "www", TTL: "60", Type: "TXT", IP: "keep"})
+
+	_, err := p.SetRecords(ctx, "example.com.", []libdns.Record{
+		libdns.Address{Name: "www", TTL: time.Minute, IP: netip.MustParseAddr("192.0.2.2")},
+		libdns.Address{Name: "www", TTL: time.Minute, IP: netip.MustParseAddr("192.0.2.3")},
+	})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+
+	var values []string
+	for _, rec := range srv.Records("example.com") {
+		values = append(values, rec.IP)
+	}
+	if len(values) != 3 || values[0] != "192.0.2.2" || values[1] != "keep" || values[2] != "192.0.2.3" {
+		t.Fatalf("got %v, expected 192.0.2.2, keep and 192.0.2.3", values)
+	}
+
+	deleted, err := p.DeleteRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A"},
+	})
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("got %d deleted records, expected 2", len(deleted))
+	}
+	if got := srv.Records("example.com"); len(got) != 1 || got[0].IP != "keep" {
+		t.Errorf("got %+v, expected only TXT record", got)
+	}
+}
+
+func TestListZones(t *testing.T) {
+	p, _ := newTestProvider(t)
+
+	zones, err := p.ListZones(context.Background())
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(zones) != 1 || zones[0].Name != "example.com." {
+		t.Errorf("got %v, expected example.com.", zones)
+	}
+}
+
+func TestGetRecordsUnsetPriority(t *testing.T) {
+	p, srv := newTestProvider(t)
+	srv.AddRecord("example.com", s90test.Record{Name: "", TTL: "3600", Type: "MX", IP: "mx.example.net."})
+
+	records, err := p.GetRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("got %s, expected nil", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %d records, expected 1", len(records))
+	}
+
+	expected := libdns.RR{Name: "@", TTL: time.Hour, Type: "MX", Data: "0 mx.example.net."}
+	if got := records[0].RR(); got != expected {
+		t.Errorf("got %+v, expected %+v", got, expected)
+	}
+	if _, ok := records[0].(libdns.MX); !ok {
+		t.Errorf("got %T, expected libdns.MX", records[0])
+	}
+}
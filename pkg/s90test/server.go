@@ -0,0 +1,371 @@
+// Package s90test provides an in-memory fake of the Systems90 API for tests.
+package s90test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+
+	"barglvojtech.net/systems90api/internal/types"
+)
+
+// Record is a DNS record as stored by the fake server.
+// Fields hold the same strings as sent over the wire.
+type Record struct {
+	ID       string
+	Name     string
+	TTL      string
+	Type     string
+	IP       string
+	Priority string
+	Locked   bool
+}
+
+type domain struct {
+	id      string
+	zone    string
+	uid     string
+	records []Record
+}
+
+// Server is a fake Systems90 API server backed by httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// BodyStatusOnly makes the server answer refused requests with 200 OK,
+	// reporting the failure only in the status element of the body.
+	BodyStatusOnly bool
+
+	mu       sync.Mutex
+	lastID   int
+	users    map[string]string // uid -> password
+	sessions map[string]string // sid -> uid
+	domains  []*domain
+}
+
+// NewServer starts and returns a new fake server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		users:    make(map[string]string),
+		sessions: make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", s.login)
+	mux.HandleFunc("/logout", s.logout)
+	mux.HandleFunc("/domain_list", s.listDomains)
+	mux.HandleFunc("/domain_list_dns", s.listDNS)
+	mux.HandleFunc("/domain_add_dns", s.addDNS)
+	mux.HandleFunc("/domain_delete_dns", s.deleteDNS)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddUser registers a user that can log in with the given credentials.
+func (s *Server) AddUser(uid, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[uid] = password
+}
+
+// AddDomain registers a zone managed by the given user and returns its domain ID.
+func (s *Server) AddDomain(uid, zone string) (domainID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := &domain{
+		id:   s.nextID(),
+		zone: zone,
+		uid:  uid,
+	}
+	s.domains = append(s.domains, d)
+	return d.id
+}
+
+// AddRecord stores a record in the given zone and returns its ID.
+// The ID of the given record is ignored.
+func (s *Server) AddRecord(zone string, rec Record) (dnsID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.domainByZone(zone)
+	if d == nil {
+		panic("s90test: unknown zone " + zone)
+	}
+
+	rec.ID = s.nextID()
+	d.records = append(d.records, rec)
+	return rec.ID
+}
+
+// Records returns a copy of the records stored in the given zone.
+func (s *Server) Records(zone string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := s.domainByZone(zone)
+	if d == nil {
+		return nil
+	}
+	return slices.Clone(d.records)
+}
+
+// Sessions returns the number of active sessions.
+func (s *Server) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sessions)
+}
+
+// ExpireSessions invalidates all active sessions.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.sessions)
+}
+
+func (s *Server) nextID() string {
+	s.lastID++
+	return strconv.Itoa(s.lastID)
+}
+
+func (s *Server) domainByZone(zone string) *domain {
+	for _, d := range s.domains {
+		if d.zone == zone {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *Server) domainByID(uid, domainID string) *domain {
+	for _, d := range s.domains {
+		if d.id == domainID && d.uid == uid {
+			return d
+		}
+	}
+	return nil
+}
+
+// session returns the user of the session given by the sid parameter.
+func (s *Server) session(r *http.Request) (uid string, ok bool) {
+	uid, ok = s.sessions[r.URL.Query().Get("sid")]
+	return uid, ok
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		s.writeStatus(w, types.StatusBadRequest)
+		return
+	}
+
+	uid, password := r.PostFormValue("uid"), r.PostFormValue("password")
+	if pw, ok := s.users[uid]; !ok || pw != password || uid == "" {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	sid := "sid" + s.nextID()
+	s.sessions[sid] = uid
+
+	writeResponse(w, http.StatusOK, types.LoginResponse{
+		Status: okStatus(),
+		UID:    uid,
+		SID:    sid,
+	})
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.session(r); !ok {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	delete(s.sessions, r.URL.Query().Get("sid"))
+	writeResponse(w, http.StatusOK, types.LogoutResponse{
+		Status: okStatus(),
+	})
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uid, ok := s.session(r)
+	if !ok {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	resp := types.ListDomainsResponse{Status: okStatus()}
+	for _, d := range s.domains {
+		if d.uid == uid {
+			resp.Domains.Domains = append(resp.Domains.Domains, types.ListDomainsResponse_Domain{
+				DomainID: d.id,
+				Name:     d.zone,
+			})
+		}
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) listDNS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uid, ok := s.session(r)
+	if !ok {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	d := s.domainByID(uid, r.URL.Query().Get("domain_id"))
+	if d == nil {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	resp := types.ListDnsResponse{Status: okStatus()}
+	for _, rec := range d.records {
+		resp.Zone.Records = append(resp.Zone.Records, types.ListDnsResponse_Record{
+			DnsID:    rec.ID,
+			Name:     rec.Name,
+			TTL:      rec.TTL,
+			Type:     rec.Type,
+			IP:       rec.IP,
+			Priority: rec.Priority,
+			Locked:   rec.Locked,
+		})
+	}
+	writeResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) addDNS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uid, ok := s.session(r)
+	if !ok {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	d := s.domainByID(uid, r.URL.Query().Get("domain_id"))
+	if d == nil {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		s.writeStatus(w, types.StatusBadRequest)
+		return
+	}
+
+	rec := Record{
+		Name:     r.PostFormValue("name"),
+		TTL:      r.PostFormValue("ttl"),
+		Type:     r.PostFormValue("type"),
+		IP:       r.PostFormValue("ip"),
+		Priority: r.PostFormValue("priority"),
+	}
+	if rec.Type == "" || rec.IP == "" {
+		s.writeStatus(w, types.StatusBadRequest)
+		return
+	}
+	if ttl, err := strconv.ParseUint(rec.TTL, 10, 32); err != nil || ttl == 0 {
+		s.writeStatus(w, types.StatusBadRequest)
+		return
+	}
+
+	rec.ID = s.nextID()
+	d.records = append(d.records, rec)
+
+	writeResponse(w, http.StatusOK, types.AddDnsResponse{
+		Status: okStatus(),
+		DNSID:  rec.ID,
+	})
+}
+
+func (s *Server) deleteDNS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uid, ok := s.session(r)
+	if !ok {
+		s.writeStatus(w, types.StatusForbidden)
+		return
+	}
+
+	dnsID := r.URL.Query().Get("dns_id")
+	for _, d := range s.domains {
+		if d.uid != uid {
+			continue
+		}
+
+		i := slices.IndexFunc(d.records, func(rec Record) bool { return rec.ID == dnsID })
+		if i < 0 {
+			continue
+		}
+		if d.records[i].Locked {
+			s.writeStatus(w, types.StatusForbidden)
+			return
+		}
+
+		d.records = slices.Delete(d.records, i, i+1)
+		writeResponse(w, http.StatusOK, types.DeleteDnsResponse{
+			Status: okStatus(),
+		})
+		return
+	}
+
+	s.writeStatus(w, types.StatusBadRequest)
+}
+
+func okStatus() types.Status {
+	return types.Status{
+		Code: types.StatusOk,
+		Text: "OK",
+	}
+}
+
+// writeStatus writes a response carrying only a non-OK status.
+func (s *Server) writeStatus(w http.ResponseWriter, code types.StatusCode) {
+	httpStatus := http.StatusBadRequest
+	switch {
+	case s.BodyStatusOnly:
+		httpStatus = http.StatusOK
+	case code == types.StatusForbidden:
+		httpStatus = http.StatusForbidden
+	}
+
+	writeResponse(w, httpStatus, struct {
+		Status types.Status `xml:"status"`
+	}{
+		Status: types.Status{
+			Code: code,
+			Text: string(code),
+		},
+	})
+}
+
+func writeResponse(w http.ResponseWriter, httpStatus int, resp any) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(httpStatus)
+
+	enc := xml.NewEncoder(w)
+	_ = enc.EncodeElement(resp, xml.StartElement{Name: xml.Name{Local: "response"}})
+}